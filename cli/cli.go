@@ -0,0 +1,92 @@
+// Package cli implements the scraper's subcommands. main.go only hands
+// os.Args over to Run.
+package cli
+
+import (
+	"airbnb-scraper/config"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+var commands = []command{
+	{"scrape", "scrape Airbnb, save to CSV and PostgreSQL, print the report (default)", runScrape},
+	{"report", "print the insights report from PostgreSQL or a CSV file without scraping", runReport},
+	{"export", "dump stored listings from PostgreSQL to CSV or JSON", runExport},
+	{"migrate", "create or update the PostgreSQL schema only", runMigrate},
+	{"stats", "show row counts and the last scrape time", runStats},
+}
+
+// Run dispatches to the subcommand named by args[0] and returns the
+// process exit code. With no subcommand (or only flags) it runs scrape,
+// so `go run main.go` keeps working as before.
+func Run(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") && args[0] != "-h" && args[0] != "--help" {
+		return runScrape(args)
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" {
+		usage()
+		return 0
+	}
+
+	for _, c := range commands {
+		if c.name == name {
+			return c.run(args[1:])
+		}
+	}
+
+	utils.Error("Unknown command %q", name)
+	usage()
+	return 2
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: airbnb-scraper <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-11s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run 'airbnb-scraper <command> -h' for the flags of a command.")
+}
+
+// loadConfig parses the config flags (plus any already registered on fs)
+// and reports the exit code to use when parsing did not succeed.
+func loadConfig(fs *flag.FlagSet, args []string) (*config.Config, int, bool) {
+	cfg, err := config.Load(fs, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil, 0, false
+	}
+	if err != nil {
+		utils.Error("%v", err)
+		return nil, 2, false
+	}
+	return cfg, 0, true
+}
+
+// openDB connects to PostgreSQL and makes sure the schema exists.
+func openDB(cfg *config.Config) (*storage.PostgresWriter, error) {
+	pgWriter, err := storage.NewPostgresWriter(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect PostgreSQL: %w", err)
+	}
+
+	if err := pgWriter.EnsureSchema(); err != nil {
+		pgWriter.Close()
+		return nil, fmt.Errorf("failed to ensure PostgreSQL schema: %w", err)
+	}
+	return pgWriter, nil
+}
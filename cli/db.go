@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"airbnb-scraper/utils"
+	"flag"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// runMigrate creates or updates the PostgreSQL schema and exits.
+func runMigrate(args []string) int {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	cfg, code, ok := loadConfig(fs, args)
+	if !ok {
+		return code
+	}
+
+	pgWriter, err := openDB(cfg)
+	if err != nil {
+		utils.Error("%v", err)
+		return 1
+	}
+	defer pgWriter.Close()
+
+	utils.Success("PostgreSQL schema is up to date (%s@%s:%d/%s)", cfg.DBUser, cfg.DBHost, cfg.DBPort, cfg.DBName)
+	return 0
+}
+
+// runStats prints row counts and when data was last scraped.
+func runStats(args []string) int {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	cfg, code, ok := loadConfig(fs, args)
+	if !ok {
+		return code
+	}
+
+	pgWriter, err := openDB(cfg)
+	if err != nil {
+		utils.Error("%v", err)
+		return 1
+	}
+	defer pgWriter.Close()
+
+	stats, err := pgWriter.Stats()
+	if err != nil {
+		utils.Error("%v", err)
+		return 1
+	}
+
+	lastRun, err := pgWriter.LastRun()
+	if err != nil {
+		utils.Error("%v", err)
+		return 1
+	}
+
+	platforms := make([]string, 0, len(stats.ByPlatform))
+	for p := range stats.ByPlatform {
+		platforms = append(platforms, p)
+	}
+	sort.Strings(platforms)
+
+	fmt.Println()
+	fmt.Println("┌──────────────────────────────────────────────────────────────┐")
+	fmt.Println("│                        Database Stats                        │")
+	fmt.Println("├───────────────────────────────┬──────────────────────────────┤")
+	fmt.Printf("│ %-29s │ %-28d │\n", "Total Listings", stats.TotalListings)
+	fmt.Printf("│ %-29s │ %-28d │\n", "Price/Rating Observations", stats.Observations)
+	for _, p := range platforms {
+		fmt.Printf("│ %-29s │ %-28d │\n", "Listings ("+p+")", stats.ByPlatform[p])
+	}
+	fmt.Printf("│ %-29s │ %-28d │\n", "Distinct Locations", stats.Locations)
+	fmt.Printf("│ %-29s │ %-28s │\n", "First Scraped", formatTime(stats.FirstScrapedAt))
+	fmt.Printf("│ %-29s │ %-28s │\n", "Last Scraped", formatTime(stats.LastScrapedAt))
+	if lastRun != nil {
+		fmt.Println("├───────────────────────────────┼──────────────────────────────┤")
+		fmt.Printf("│ %-29s │ %-28s │\n", "Last Run", lastRun.ID)
+		fmt.Printf("│ %-29s │ %-28s │\n", "Last Run Status", lastRun.Status)
+		fmt.Printf("│ %-29s │ %-28s │\n", "Last Run Finished", formatTime(lastRun.FinishedAt))
+		fmt.Printf("│ %-29s │ %-28s │\n", "Last Run Version", lastRun.Version)
+		fmt.Printf("│ %-29s │ %-28s │\n", "Last Run Properties (ok/fail)",
+			fmt.Sprintf("%d / %d", lastRun.PropertiesScraped, lastRun.PropertiesFailed))
+	}
+	fmt.Println("└───────────────────────────────┴──────────────────────────────┘")
+	return 0
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
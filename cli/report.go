@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// runReport prints the insights report for already-stored listings.
+func runReport(args []string) int {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	from := fs.String("from", "db", "where to read listings from: db or csv")
+	input := fs.String("input", "", "CSV file to read with --from csv (default: csv_path)")
+
+	cfg, code, ok := loadConfig(fs, args)
+	if !ok {
+		return code
+	}
+
+	listings, err := loadListings(cfg, *from, *input)
+	if err != nil {
+		utils.Error("%v", err)
+		return 1
+	}
+	if len(listings) == 0 {
+		utils.Warn("No listings found.")
+		return 0
+	}
+
+	report := services.GenerateReport(listings)
+	services.PrintReport(report)
+	return 0
+}
+
+// runExport dumps stored listings from PostgreSQL into a CSV or JSON file.
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", "csv", "output format: csv or json")
+	out := fs.String("out", "", "output file (default: output/export.<format>)")
+
+	cfg, code, ok := loadConfig(fs, args)
+	if !ok {
+		return code
+	}
+
+	*format = strings.ToLower(*format)
+	if *format != "csv" && *format != "json" {
+		utils.Error("Unknown export format %q (want csv or json)", *format)
+		return 2
+	}
+	if *out == "" {
+		*out = filepath.Join("output", "export."+*format)
+	}
+
+	listings, err := loadListings(cfg, "db", "")
+	if err != nil {
+		utils.Error("%v", err)
+		return 1
+	}
+
+	if err := os.MkdirAll(filepath.Dir(*out), 0755); err != nil {
+		utils.Error("Could not create output dir: %v", err)
+		return 1
+	}
+	file, err := os.Create(*out)
+	if err != nil {
+		utils.Error("Could not create file: %v", err)
+		return 1
+	}
+	defer file.Close()
+
+	if *format == "json" {
+		err = storage.WriteJSON(file, listings)
+	} else {
+		err = storage.WriteCSV(file, listings)
+	}
+	if err != nil {
+		utils.Error("Export failed: %v", err)
+		return 1
+	}
+
+	utils.Success("Exported %d listings → %s", len(listings), *out)
+	return 0
+}
+
+func loadListings(cfg *config.Config, from, input string) ([]models.Listing, error) {
+	switch from {
+	case "csv":
+		if input == "" {
+			input = cfg.CSVPath
+		}
+		listings, err := storage.ReadCSV(input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", input, err)
+		}
+		utils.Info("Loaded %d listings from %s", len(listings), input)
+		return listings, nil
+
+	case "db":
+		pgWriter, err := openDB(cfg)
+		if err != nil {
+			return nil, err
+		}
+		defer pgWriter.Close()
+
+		listings, err := pgWriter.ReadListings()
+		if err != nil {
+			return nil, err
+		}
+		utils.Info("Loaded %d listings from PostgreSQL", len(listings))
+		return listings, nil
+
+	default:
+		return nil, fmt.Errorf("unknown source %q (want db or csv)", from)
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"airbnb-scraper/config"
+	"airbnb-scraper/scraper/airbnb"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+)
+
+// runRecord keeps the scrape_runs row of the current run up to date.
+// With --skip-db there is no database and it only carries the run ID.
+type runRecord struct {
+	db         *storage.PostgresWriter
+	checkpoint *airbnb.Checkpoint
+	run        storage.ScrapeRun
+}
+
+// startRun records the run as running. It fails when the row cannot be
+// written, since listings saved later reference it.
+func startRun(db *storage.PostgresWriter, checkpoint *airbnb.Checkpoint, cfg *config.Config) (*runRecord, error) {
+	r := &runRecord{
+		db:         db,
+		checkpoint: checkpoint,
+		run: storage.ScrapeRun{
+			ID:        checkpoint.RunID,
+			StartedAt: checkpoint.StartedAt,
+			Status:    storage.RunRunning,
+			Version:   version(),
+			Config:    cfg.Snapshot(),
+		},
+	}
+	if db == nil {
+		return r, nil
+	}
+	if err := db.StartRun(r.run); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// finish stores the final status, the counts from the checkpoint and an
+// error summary (failed pages, plus failure if the run itself failed).
+func (r *runRecord) finish(status string, listingsSaved int, failure error) {
+	if r.db == nil {
+		return
+	}
+
+	counts := r.checkpoint.Counts()
+	r.run.Status = status
+	r.run.SectionsVisited = r.checkpoint.SectionsVisited()
+	r.run.PropertiesScraped = counts[airbnb.PropertyDone]
+	r.run.PropertiesFailed = counts[airbnb.PropertyFailed]
+	r.run.ListingsSaved = listingsSaved
+	r.run.ErrorSummary = r.checkpoint.ErrorSummary(5)
+	if failure != nil {
+		summary := "run: " + failure.Error()
+		if r.run.ErrorSummary != "" {
+			summary += "; " + r.run.ErrorSummary
+		}
+		r.run.ErrorSummary = summary
+	}
+
+	if err := r.db.FinishRun(r.run); err != nil {
+		utils.Warn("%v", err)
+		return
+	}
+	utils.Info("Run %s recorded as %s", r.run.ID, status)
+}
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"airbnb-scraper/models"
+	"airbnb-scraper/scraper/airbnb"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+	"airbnb-scraper/utils"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// runScrape is the full pipeline: scrape → clean → CSV → PostgreSQL → report.
+func runScrape(args []string) int {
+	fs := flag.NewFlagSet("scrape", flag.ContinueOnError)
+	skipDB := fs.Bool("skip-db", false, "do not write listings to PostgreSQL")
+	skipReport := fs.Bool("skip-report", false, "do not print the insights report")
+	resume := fs.String("resume", "", "resume the interrupted run with this ID, skipping pages it already scraped")
+
+	cfg, code, ok := loadConfig(fs, args)
+	if !ok {
+		return code
+	}
+
+	utils.Info("Scraper starting | pages=%d workers=%d delay=%v-%v",
+		cfg.MaxPages, cfg.MaxWorkers, cfg.MinDelay, cfg.MaxDelay)
+	if cfg.SearchMode() {
+		utils.Info("Search mode | location=%q dates=%s..%s adults=%d children=%d pets=%d",
+			cfg.SearchLocation, cfg.CheckIn, cfg.CheckOut, cfg.Adults, cfg.Children, cfg.Pets)
+	}
+
+	checkpoint, err := openCheckpoint(cfg.CheckpointDir, *resume)
+	if err != nil {
+		utils.Error("%v", err)
+		return 1
+	}
+	utils.Info("Run ID %s (resume with: scrape --resume %s)", checkpoint.RunID, checkpoint.RunID)
+
+	var pgWriter *storage.PostgresWriter
+	if !*skipDB {
+		pgWriter, err = openDB(cfg)
+		if err != nil {
+			utils.Error("%v", err)
+			return 1
+		}
+		defer pgWriter.Close()
+	}
+
+	record, err := startRun(pgWriter, checkpoint, cfg)
+	if err != nil {
+		utils.Error("%v", err)
+		return 1
+	}
+
+	// Whatever path the run leaves by, its scrape_runs row is finalized.
+	status, saved := storage.RunFailed, 0
+	var failure error
+	defer func() { record.finish(status, saved, failure) }()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	finished := make(chan struct{})
+	defer close(finished)
+	go func() {
+		<-ctx.Done()
+		select {
+		case <-finished: // stop() on return, not a signal
+			return
+		default:
+		}
+		// Restore default signal handling so a second Ctrl-C kills the
+		// process instead of waiting for the drain.
+		stop()
+		utils.Warn("Interrupted: finishing in-flight pages (up to %v), then saving what was scraped. Ctrl-C again to quit now.",
+			cfg.ShutdownTimeout)
+	}()
+
+	scraper, err := airbnb.NewScraper(ctx, cfg)
+	if err != nil {
+		failure = fmt.Errorf("could not start scraper: %w", err)
+		utils.Error("%v", failure)
+		return 1
+	}
+	defer scraper.Close()
+
+	pool := airbnb.NewWorkerPool(scraper, cfg, checkpoint)
+	listings := pool.Run(ctx)
+	status = storage.RunCompleted
+	if ctx.Err() != nil {
+		status = storage.RunInterrupted
+		utils.Warn("Run interrupted; saving the %d listings collected so far", len(listings))
+	}
+	if counts := checkpoint.Counts(); counts[airbnb.PropertyPending]+counts[airbnb.PropertyFailed] > 0 {
+		utils.Warn("%d properties pending, %d failed; retry them with: scrape --resume %s",
+			counts[airbnb.PropertyPending], counts[airbnb.PropertyFailed], checkpoint.RunID)
+	}
+
+	if len(listings) == 0 {
+		utils.Warn("No listings scraped.")
+		return 0
+	}
+
+	cleanedListings := services.CleanListings(listings)
+	if len(cleanedListings) == 0 {
+		utils.Warn("No valid listings after cleaning.")
+		return 0
+	}
+
+	writer := storage.NewCSVWriter(cfg.CSVPath)
+	if err := writer.Write(cleanedListings); err != nil {
+		status, failure = storage.RunFailed, fmt.Errorf("failed to save CSV: %w", err)
+		utils.Error("%v", failure)
+		return 1
+	}
+
+	if pgWriter != nil {
+		meta := storage.BatchMeta{RunID: checkpoint.RunID, CheckIn: cfg.CheckIn, CheckOut: cfg.CheckOut, CardsOnly: cfg.CardsOnly}
+		if err := pgWriter.WriteBatch(meta, cleanedListings); err != nil {
+			status, failure = storage.RunFailed, fmt.Errorf("failed to save listings to PostgreSQL: %w", err)
+			utils.Error("%v", failure)
+			return 1
+		}
+		saved = len(cleanedListings)
+		utils.Success("Saved %d cleaned listings to PostgreSQL", len(cleanedListings))
+	}
+
+	printSummary(cleanedListings)
+	if !*skipReport {
+		report := services.GenerateReport(cleanedListings)
+		services.PrintReport(report)
+	}
+	return 0
+}
+
+// openCheckpoint starts a checkpoint for a new run, or loads the one of
+// the run being resumed.
+func openCheckpoint(dir, resumeID string) (*airbnb.Checkpoint, error) {
+	if resumeID == "" {
+		return airbnb.NewCheckpoint(dir, airbnb.NewRunID())
+	}
+
+	checkpoint, err := airbnb.LoadCheckpoint(dir, resumeID)
+	if err != nil {
+		return nil, err
+	}
+	counts := checkpoint.Counts()
+	utils.Info("Resuming run %s: %d done, %d pending, %d failed",
+		resumeID, counts[airbnb.PropertyDone], counts[airbnb.PropertyPending], counts[airbnb.PropertyFailed])
+	return checkpoint, nil
+}
+
+func printSummary(listings []models.Listing) {
+	fmt.Println()
+	fmt.Println("╔══════════════════════════════════════════════╗")
+	fmt.Println("║                SCRAPE COMPLETE               ║")
+	fmt.Println("╠══════════════════════════════════════════════╣")
+	fmt.Printf("║  Total listings : %-26d║\n", len(listings))
+	fmt.Println("╚══════════════════════════════════════════════╝")
+	fmt.Println()
+}
@@ -0,0 +1,40 @@
+package cli
+
+import "runtime/debug"
+
+// Version is the binary version recorded with every scrape run. Release
+// builds set it with:
+//
+//	go build -ldflags "-X airbnb-scraper/cli.Version=v1.4.0"
+var Version = "dev"
+
+// version returns Version, or for unreleased builds "dev" plus the VCS
+// revision Go stamped into the binary, when there is one.
+func version() string {
+	if Version != "dev" {
+		return Version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return Version
+	}
+
+	var revision, modified string
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			if s.Value == "true" {
+				modified = "-dirty"
+			}
+		}
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if revision == "" {
+		return Version
+	}
+	return Version + "+" + revision + modified
+}
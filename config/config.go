@@ -2,24 +2,101 @@ package config
 
 import "time"
 
+// Config holds every runtime setting. The `key` tag is the name used in
+// config files, the AIRBNB_SCRAPER_<KEY> environment variable and the
+// --<key> command-line flag (underscores become dashes). See Load.
 type Config struct {
-	BaseURL        string
-	MaxPages       int
-	MaxWorkers     int
-	RequestTimeout time.Duration
-	MinDelay       time.Duration
-	MaxDelay       time.Duration
-	MaxRetries     int
-	Headless       bool
-	CSVPath        string
-	DBHost         string
-	DBPort         int
-	DBUser         string
-	DBPassword     string
-	DBName         string
-	DBSSLMode      string
+	BaseURL        string        `key:"base_url" help:"Airbnb origin the crawl starts from"`
+	MaxPages       int           `key:"max_pages" help:"number of sections processed"`
+	MaxWorkers     int           `key:"max_workers" help:"concurrent detail-page workers"`
+	RequestTimeout time.Duration `key:"request_timeout" help:"timeout per page load"`
+	MinDelay       time.Duration `key:"min_delay" help:"minimum random delay between detail pages"`
+	MaxDelay       time.Duration `key:"max_delay" help:"maximum random delay between detail pages"`
+	MaxRetries     int           `key:"max_retries" help:"attempts per detail page"`
+	Headless       bool          `key:"headless" help:"run Chrome headless"`
+	CSVPath        string        `key:"csv_path" help:"CSV output file"`
+	DBHost         string        `key:"db_host" help:"PostgreSQL host"`
+	DBPort         int           `key:"db_port" help:"PostgreSQL port"`
+	DBUser         string        `key:"db_user" help:"PostgreSQL user"`
+	DBPassword     string        `key:"db_password" help:"PostgreSQL password"`
+	DBName         string        `key:"db_name" help:"PostgreSQL database"`
+	DBSSLMode      string        `key:"db_sslmode" help:"PostgreSQL sslmode"`
+
+	// Search result depth — CardsPerPage is how many listing cards are
+	// taken from each results page (0 = all of them), MaxSectionPages is
+	// how many results pages are followed per section via "Next". The
+	// defaults take every card of up to five pages; Airbnb shows about 18
+	// cards per page and stops paging at 15.
+	CardsPerPage    int `key:"cards_per_page" help:"listing cards taken per results page (0 = all)"`
+	MaxSectionPages int `key:"max_section_pages" help:"results pages followed per section"`
+
+	// CardsOnly builds listings from the search results cards alone
+	// (title, price, rating, review count, thumbnail) and never opens a
+	// detail page, so the detail stages (hosts, reviews, calendar,
+	// photos) are skipped too.
+	CardsOnly bool `key:"cards_only" help:"build listings from search results cards without opening detail pages"`
+
+	// ShutdownTimeout is how long pages already loading may keep going
+	// after Ctrl-C/SIGTERM before the browser is torn down.
+	ShutdownTimeout time.Duration `key:"shutdown_timeout" help:"time in-flight pages get to finish after an interrupt"`
+
+	// CheckpointDir holds one <run-id>.json crawl frontier per run, used
+	// by `scrape --resume <run-id>`.
+	CheckpointDir string `key:"checkpoint_dir" help:"directory for run checkpoints (resume state)"`
+
+	// HostProfiles opens each host's profile page once per run to read
+	// how many listings they have; detail pages do not show it. It is off
+	// by default because it adds a page load per distinct host to every
+	// run; without it Host.ListingCount stays 0 and hosts are classed as
+	// professional by Airbnb's label or a company name only.
+	HostProfiles bool `key:"host_profiles" help:"visit each host's profile once per run for their listing count"`
+
+	// MaxReviews enables the review stage: each detail page's reviews
+	// modal is opened and scrolled until this many reviews are loaded.
+	MaxReviews int `key:"max_reviews" help:"reviews scraped per listing from the reviews modal (0 = skip reviews)"`
+
+	// CalendarMonths enables the calendar stage: each listing's
+	// availability calendar is read this many months ahead, from the
+	// calendar API response or by paging through the calendar widget.
+	CalendarMonths int `key:"calendar_months" help:"months of availability calendar scraped per listing (0 = skip the calendar)"`
+
+	// PhotosDir enables photo downloads into a content-addressed store:
+	// files are named by the SHA-256 of the image and an index maps photo
+	// URLs to them, so an image is fetched once across runs and stored
+	// once however many listings or URLs show it.
+	PhotosDir string `key:"photos_dir" help:"content-addressed directory listing photos are downloaded into (empty = record photo URLs only)"`
+
+	// SelectorsPath points at a selector file overriding the embedded
+	// default extraction chains (scraper/airbnb/selectors/default.yaml).
+	SelectorsPath string `key:"selectors_path" help:"selector file overriding the embedded extraction chains"`
+
+	// Fixtures — "record" saves every rendered page and captured API body
+	// to FixturesDir during a live run, "replay" serves them back from a
+	// local server so extraction runs fully offline.
+	FixturesMode string `key:"fixtures_mode" help:"fixture mode: record or replay (empty = live)"`
+	FixturesDir  string `key:"fixtures_dir" help:"directory for recorded page fixtures"`
+
+	// Search mode — when SearchLocation is set the scraper skips the
+	// homepage sections and builds a /s/<place>/homes URL from these.
+	SearchLocation string `key:"search_location" help:"destination to search, e.g. \"Kuala Lumpur, Malaysia\""`
+	CheckIn        string `key:"check_in" help:"check-in date (YYYY-MM-DD)"`
+	CheckOut       string `key:"check_out" help:"check-out date (YYYY-MM-DD)"`
+	Adults         int    `key:"adults" help:"number of adults"`
+	Children       int    `key:"children" help:"number of children"`
+	Pets           int    `key:"pets" help:"number of pets"`
+	MinPrice       int    `key:"min_price" help:"minimum nightly price (0 = no bound)"`
+	MaxPrice       int    `key:"max_price" help:"maximum nightly price (0 = no bound)"`
 }
 
+// MaxCalendarMonths is how far ahead Airbnb calendars go.
+const MaxCalendarMonths = 12
+
+// Fixture modes for Config.FixturesMode.
+const (
+	FixturesRecord = "record"
+	FixturesReplay = "replay"
+)
+
 func DefaultConfig() *Config {
 	return &Config{
 		BaseURL:        "https://www.airbnb.com/",
@@ -37,5 +114,17 @@ func DefaultConfig() *Config {
 		DBPassword:     "postgres",
 		DBName:         "airbnb_scraper",
 		DBSSLMode:      "disable",
+
+		MaxSectionPages: 5,
+		ShutdownTimeout: 30 * time.Second,
+		CheckpointDir:   "output/runs",
+		FixturesDir:     "fixtures",
+		Adults:          1,
 	}
 }
+
+// SearchMode reports whether the run targets a specific destination
+// instead of crawling the homepage sections.
+func (c *Config) SearchMode() bool {
+	return c.SearchLocation != ""
+}
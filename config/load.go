@@ -0,0 +1,211 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is prepended to every upper-cased config key to form its
+// environment variable, e.g. max_workers → AIRBNB_SCRAPER_MAX_WORKERS.
+const EnvPrefix = "AIRBNB_SCRAPER_"
+
+// Load builds the runtime config in layers, each one overriding the last:
+//
+//  1. DefaultConfig()
+//  2. config file (--config, or AIRBNB_SCRAPER_CONFIG) — .yaml/.yml/.toml
+//  3. AIRBNB_SCRAPER_* environment variables
+//  4. command-line flags
+//
+// The config flags are registered on fs next to any flags the caller
+// already defined (subcommand options), then args is parsed. The result
+// is validated before it is returned. flag.ErrHelp is returned unchanged
+// when -h/--help was requested.
+func Load(fs *flag.FlagSet, args []string) (*Config, error) {
+	cfg := DefaultConfig()
+
+	// Flags are parsed into a scratch copy first so that only the ones
+	// actually given on the command line override the file and env layers.
+	flagCfg := DefaultConfig()
+	configPath := fs.String("config", "", "path to a YAML or TOML config file (env "+EnvPrefix+"CONFIG)")
+	flagFields := make(map[string]*field)
+	for _, f := range fields(flagCfg) {
+		name := strings.ReplaceAll(f.key, "_", "-")
+		fs.Var(f, name, f.help)
+		flagFields[name] = f
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	path := *configPath
+	if path == "" {
+		path = os.Getenv(EnvPrefix + "CONFIG")
+	}
+	if path != "" {
+		if err := loadFile(cfg, path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := loadEnv(cfg); err != nil {
+		return nil, err
+	}
+
+	targets := make(map[string]*field)
+	for _, f := range fields(cfg) {
+		targets[strings.ReplaceAll(f.key, "_", "-")] = f
+	}
+	fs.Visit(func(fl *flag.Flag) {
+		if src, ok := flagFields[fl.Name]; ok {
+			targets[fl.Name].v.Set(src.v)
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read config file: %w", err)
+	}
+
+	raw := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return fmt.Errorf("unsupported config file type %q (want .yaml, .yml or .toml)", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("could not parse config file %s: %w", path, err)
+	}
+
+	byKey := make(map[string]*field)
+	for _, f := range fields(cfg) {
+		byKey[f.key] = f
+	}
+	for key, value := range raw {
+		f, ok := byKey[key]
+		if !ok {
+			return fmt.Errorf("%s: unknown config key %q", path, key)
+		}
+		if err := f.Set(fmt.Sprint(value)); err != nil {
+			return fmt.Errorf("%s: %s: %w", path, key, err)
+		}
+	}
+	return nil
+}
+
+func loadEnv(cfg *Config) error {
+	for _, f := range fields(cfg) {
+		name := EnvPrefix + strings.ToUpper(f.key)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := f.Set(value); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Snapshot returns every setting as key → value text, for recording
+// which config a run used. The database password is redacted.
+func (c *Config) Snapshot() map[string]string {
+	out := make(map[string]string)
+	for _, f := range fields(c) {
+		out[f.key] = f.String()
+	}
+	if c.DBPassword != "" {
+		out["db_password"] = "redacted"
+	}
+	return out
+}
+
+// field adapts one tagged Config field to flag.Value so the same string
+// parsing is shared by the file, environment and flag layers.
+type field struct {
+	key  string
+	help string
+	v    reflect.Value
+}
+
+func fields(cfg *Config) []*field {
+	rv := reflect.ValueOf(cfg).Elem()
+	rt := rv.Type()
+
+	var out []*field
+	for i := 0; i < rt.NumField(); i++ {
+		key := rt.Field(i).Tag.Get("key")
+		if key == "" {
+			continue
+		}
+		out = append(out, &field{key: key, help: rt.Field(i).Tag.Get("help"), v: rv.Field(i)})
+	}
+	return out
+}
+
+func (f *field) String() string {
+	if f == nil || !f.v.IsValid() {
+		return ""
+	}
+	return fmt.Sprint(f.v.Interface())
+}
+
+func (f *field) Set(s string) error {
+	s = strings.TrimSpace(s)
+
+	if f.v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q (e.g. 30s, 2m)", s)
+		}
+		f.v.SetInt(int64(d))
+		return nil
+	}
+
+	switch f.v.Kind() {
+	case reflect.String:
+		f.v.SetString(s)
+	case reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		f.v.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		f.v.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported config type %s", f.v.Type())
+	}
+	return nil
+}
+
+// IsBoolFlag lets boolean keys be passed as a bare --headless.
+func (f *field) IsBoolFlag() bool {
+	return f.v.IsValid() && f.v.Kind() == reflect.Bool
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func load(args ...string) (*Config, error) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return Load(fs, args)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeConfig(t, "config.yaml", "max_workers: 2\nmin_delay: 1s\nheadless: false\nsearch_location: Bali\n")
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		args        []string
+		wantWorkers int
+		wantDelay   time.Duration
+		wantHead    bool
+	}{
+		{"defaults", nil, nil, 3, 3 * time.Second, true},
+		{"file", nil, []string{"--config", file}, 2, time.Second, false},
+		{"file from env", map[string]string{"AIRBNB_SCRAPER_CONFIG": file}, nil, 2, time.Second, false},
+		{"env over file", map[string]string{"AIRBNB_SCRAPER_MAX_WORKERS": "5"}, []string{"--config", file}, 5, time.Second, false},
+		{"flag over env", map[string]string{"AIRBNB_SCRAPER_MAX_WORKERS": "5"}, []string{"--config", file, "--max-workers", "7"}, 7, time.Second, false},
+		{"flag at its default value still wins", nil, []string{"--config", file, "--headless", "--min-delay", "3s"}, 2, 3 * time.Second, true},
+		{"unset flags keep file values", nil, []string{"--config", file, "--max-pages", "1"}, 2, time.Second, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg, err := load(tt.args...)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.MaxWorkers != tt.wantWorkers || cfg.MinDelay != tt.wantDelay || cfg.Headless != tt.wantHead {
+				t.Errorf("max_workers %d, min_delay %v, headless %v; want %d, %v, %v",
+					cfg.MaxWorkers, cfg.MinDelay, cfg.Headless, tt.wantWorkers, tt.wantDelay, tt.wantHead)
+			}
+		})
+	}
+}
+
+func TestLoadTOML(t *testing.T) {
+	file := writeConfig(t, "config.toml", "max_pages = 9\nbase_url = \"https://www.airbnb.co.uk/\"\n")
+	cfg, err := load("--config", file)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.MaxPages != 9 || cfg.BaseURL != "https://www.airbnb.co.uk/" {
+		t.Errorf("max_pages %d, base_url %q", cfg.MaxPages, cfg.BaseURL)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		args    func(t *testing.T) []string
+		wantErr string
+	}{
+		{"unknown file key", nil, func(t *testing.T) []string {
+			return []string{"--config", writeConfig(t, "c.yaml", "max_wokers: 2\n")}
+		}, `unknown config key "max_wokers"`},
+		{"bad file value", nil, func(t *testing.T) []string {
+			return []string{"--config", writeConfig(t, "c.yaml", "request_timeout: soon\n")}
+		}, `invalid duration "soon"`},
+		{"unsupported file type", nil, func(t *testing.T) []string {
+			return []string{"--config", writeConfig(t, "c.json", "{}")}
+		}, "unsupported config file type"},
+		{"missing file", nil, func(t *testing.T) []string {
+			return []string{"--config", filepath.Join(t.TempDir(), "none.yaml")}
+		}, "could not read config file"},
+		{"bad env value", map[string]string{"AIRBNB_SCRAPER_HEADLESS": "maybe"}, func(t *testing.T) []string {
+			return nil
+		}, "AIRBNB_SCRAPER_HEADLESS"},
+		{"bad flag value", nil, func(t *testing.T) []string {
+			return []string{"--max-pages", "many"}
+		}, `invalid integer "many"`},
+		{"extra arguments", nil, func(t *testing.T) []string {
+			return []string{"scrape"}
+		}, "unexpected arguments: scrape"},
+		{"invalid result", nil, func(t *testing.T) []string {
+			return []string{"--max-workers", "0"}
+		}, "max_workers must be at least 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := load(tt.args(t)...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := load("-h"); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("Load(-h) = %v, want flag.ErrHelp", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     func(c *Config)
+		wantErr []string
+	}{
+		{"defaults", func(c *Config) {}, nil},
+		{"calendar months at the limit", func(c *Config) { c.CalendarMonths = MaxCalendarMonths }, nil},
+		{"replay with a directory", func(c *Config) { c.FixturesMode = FixturesReplay }, nil},
+		{"relative base URL", func(c *Config) { c.BaseURL = "airbnb.com" }, []string{"must be an absolute URL"}},
+		{"delays out of order", func(c *Config) { c.MinDelay, c.MaxDelay = 5*time.Second, time.Second }, []string{"greater than max_delay"}},
+		{"calendar months too far", func(c *Config) { c.CalendarMonths = MaxCalendarMonths + 1 }, []string{"calendar_months must be between"}},
+		{"unknown fixtures mode", func(c *Config) { c.FixturesMode = "rewind" }, []string{`fixtures_mode "rewind"`}},
+		{"fixtures mode without a directory", func(c *Config) { c.FixturesMode, c.FixturesDir = FixturesRecord, "" }, []string{"fixtures_dir is required"}},
+		{"search dates", func(c *Config) { c.CheckIn = "2026-05-08" }, []string{"must be set together"}},
+		{"every problem at once", func(c *Config) {
+			c.MaxPages, c.MaxWorkers, c.DBPort = 0, 0, 70000
+		}, []string{"max_pages", "max_workers", "db_port 70000"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.set(cfg)
+			err := cfg.Validate()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate passed, want errors %q", tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
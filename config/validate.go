@@ -0,0 +1,120 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// DateLayout is the format used for CheckIn/CheckOut.
+const DateLayout = "2006-01-02"
+
+// Validate rejects settings the scraper cannot run with. All problems are
+// reported together so a bad config file can be fixed in one pass.
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(format string, a ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, a...))
+	}
+
+	if u, err := url.Parse(c.BaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+		add("base_url %q must be an absolute URL", c.BaseURL)
+	}
+	if c.MaxPages < 1 {
+		add("max_pages must be at least 1 (got %d)", c.MaxPages)
+	}
+	if c.MaxWorkers < 1 {
+		add("max_workers must be at least 1 (got %d)", c.MaxWorkers)
+	}
+	if c.MaxRetries < 1 {
+		add("max_retries must be at least 1 (got %d)", c.MaxRetries)
+	}
+	if c.RequestTimeout <= 0 {
+		add("request_timeout must be positive (got %v)", c.RequestTimeout)
+	}
+	if c.MinDelay < 0 {
+		add("min_delay cannot be negative (got %v)", c.MinDelay)
+	}
+	if c.MinDelay > c.MaxDelay {
+		add("min_delay %v is greater than max_delay %v", c.MinDelay, c.MaxDelay)
+	}
+	if c.CardsPerPage < 0 {
+		add("cards_per_page cannot be negative (got %d, use 0 for all)", c.CardsPerPage)
+	}
+	if c.MaxReviews < 0 {
+		add("max_reviews cannot be negative (got %d, use 0 to skip reviews)", c.MaxReviews)
+	}
+	if c.CalendarMonths < 0 || c.CalendarMonths > MaxCalendarMonths {
+		add("calendar_months must be between 0 and %d (got %d, use 0 to skip the calendar)", MaxCalendarMonths, c.CalendarMonths)
+	}
+	if c.MaxSectionPages < 1 {
+		add("max_section_pages must be at least 1 (got %d)", c.MaxSectionPages)
+	}
+	if c.ShutdownTimeout < 0 {
+		add("shutdown_timeout cannot be negative (got %v)", c.ShutdownTimeout)
+	}
+	switch c.FixturesMode {
+	case "", FixturesRecord, FixturesReplay:
+	default:
+		add("fixtures_mode %q must be %q, %q or empty", c.FixturesMode, FixturesRecord, FixturesReplay)
+	}
+	if c.FixturesMode != "" && c.FixturesDir == "" {
+		add("fixtures_dir is required when fixtures_mode is set")
+	}
+	if c.CheckpointDir == "" {
+		add("checkpoint_dir is empty")
+	}
+	if c.CSVPath == "" {
+		add("csv_path is empty")
+	}
+	if c.DBPort < 1 || c.DBPort > 65535 {
+		add("db_port %d is out of range", c.DBPort)
+	}
+	if c.DBHost == "" || c.DBName == "" {
+		add("db_host and db_name are required")
+	}
+
+	if err := c.ValidateSearch(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// ValidateSearch checks the search mode settings: dates must be set
+// together and in order, guest counts and price bounds non-negative.
+func (c *Config) ValidateSearch() error {
+	if c.Adults < 0 || c.Children < 0 || c.Pets < 0 {
+		return fmt.Errorf("guest counts cannot be negative")
+	}
+	if c.MinPrice < 0 || c.MaxPrice < 0 {
+		return fmt.Errorf("price bounds cannot be negative")
+	}
+	if c.MaxPrice > 0 && c.MinPrice > c.MaxPrice {
+		return fmt.Errorf("min price %d is greater than max price %d", c.MinPrice, c.MaxPrice)
+	}
+
+	if c.CheckIn == "" && c.CheckOut == "" {
+		return nil
+	}
+	if c.CheckIn == "" || c.CheckOut == "" {
+		return fmt.Errorf("check-in and check-out must be set together")
+	}
+
+	in, err := time.Parse(DateLayout, c.CheckIn)
+	if err != nil {
+		return fmt.Errorf("invalid check-in date %q (want YYYY-MM-DD)", c.CheckIn)
+	}
+	out, err := time.Parse(DateLayout, c.CheckOut)
+	if err != nil {
+		return fmt.Errorf("invalid check-out date %q (want YYYY-MM-DD)", c.CheckOut)
+	}
+	if !out.After(in) {
+		return fmt.Errorf("check-out %s must be after check-in %s", c.CheckOut, c.CheckIn)
+	}
+	return nil
+}
@@ -0,0 +1,542 @@
+package mockairbnb
+
+import (
+	"airbnb-scraper/models"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+)
+
+// The templates mirror just enough of Airbnb's markup for the scraper's
+// link, card and default selector-set lookups to work.
+var pages = template.Must(template.New("home").Parse(`<!DOCTYPE html>
+<html><head><title>Mock Airbnb</title></head>
+<body>
+<h1>Mock Airbnb</h1>
+<nav>
+{{range .}}<a href="{{.Href}}">Homes in {{.City}}</a>
+{{end}}</nav>
+</body></html>
+`))
+
+func init() {
+	template.Must(pages.New("search").Parse(`<!DOCTYPE html>
+<html><head><title>Homes in {{.City}}</title></head>
+<body>
+<h1>Homes in {{.City}}</h1>
+<div role="list">
+{{range .Cards}}<div itemprop="itemListElement">
+  <a href="/rooms/{{.ID}}?source_impression_id=p3_mock_{{.ID}}"><img src="{{.Thumbnail}}?im_w=720" alt=""><div data-testid="listing-card-title">{{.Title}}</div></a>
+  <span>{{if .Reviews}}{{.Rating}} ({{.Reviews}}){{else}}New{{end}}</span>
+  <span>${{.Nightly}} night</span>
+</div>
+{{end}}</div>
+{{if .Next}}<a aria-label="Next" href="{{.Next}}">Next</a>
+{{else}}<button aria-label="Next" disabled>Next</button>
+{{end}}</body></html>
+`))
+
+	template.Must(pages.New("profile").Parse(`<!DOCTYPE html>
+<html><head><title>{{.Name}}</title></head>
+<body>
+<h1>{{.Name}}</h1>
+<h2>{{.Name}}'s listings</h2>
+<button type="button">Show all {{.ListingCount}} listings</button>
+</body></html>
+`))
+
+	template.Must(pages.New("room").Parse(`<!DOCTYPE html>
+<html><head><title>Mock Airbnb listing</title>
+<script type="application/ld+json">{{.JSONLD}}</script>
+</head>
+<body>
+<h1>{{if .Title}}{{.Title}}{{else}}&nbsp;{{end}}</h1>
+{{with .PhotoTour}}<div data-section-id="HERO_DEFAULT">
+{{range .Hero}}  <img src="{{.Src}}?im_w=720" alt="{{.Alt}}">
+{{end}}  <button type="button" onclick="setTimeout(() => { document.getElementById('photo-tour').hidden = false; }, 100)">Show all {{.Count}} photos</button>
+</div>
+<div role="dialog" id="photo-tour" hidden>
+  <button type="button" aria-label="Close" onclick="document.getElementById('photo-tour').hidden = true">✕</button>
+{{range .Rooms}}  <section><h2>{{.Title}}</h2>{{range .Photos}}<img src="{{.Src}}?im_w=1200" alt="{{.Alt}}">{{end}}</section>
+{{end}}</div>
+{{end}}<div data-section-id="OVERVIEW_DEFAULT_V2">
+{{if and .Kind .Location}}  <h2 class="hpipapi">{{.Kind}} in {{.Location}}</h2>
+{{end}}{{if .Overview}}  <ol>{{range $i, $item := .Overview}}<li>{{if $i}}<span> · </span>{{end}}{{$item}}</li>{{end}}</ol>
+{{end}}</div>
+{{with .Booking}}<div data-section-id="BOOK_IT_SIDEBAR">
+  <div><span aria-label="${{.Total}} for {{.Nights}} nights">${{.Total}} total</span></div>
+  <div data-testid="change-dates-checkIn">{{.CheckIn}}</div>
+  <div data-testid="change-dates-checkOut">{{.CheckOut}}</div>
+  <button type="button">Reserve</button>
+  <section>
+    <div><span>${{.Nightly}} x {{.Nights}} nights</span> <span>${{.Subtotal}}</span></div>
+    <div><span>Cleaning fee</span> <span>${{.Cleaning}}</span></div>
+    <div><span>Airbnb service fee</span> <span>${{.Service}}</span></div>
+{{if .Discount}}    <div><span>Weekly stay discount</span> <span>-${{.Discount}}</span></div>
+{{end}}{{if .Taxes}}    <div><span>Taxes</span> <span>${{.Taxes}}</span></div>
+    <div><span>Total</span> <span>${{.TotalWithTaxes}}</span></div>
+{{else}}    <div><span>Total before taxes</span> <span>${{.Total}}</span></div>
+{{end}}  </section>
+</div>
+{{end}}{{if .Reviews}}<a href="#reviews">{{.Reviews}} reviews</a>
+{{end}}{{if .Description}}<div data-section-id="DESCRIPTION_DEFAULT"><span class="l1h825yc">{{.Description}}</span></div>
+{{end}}{{if .Neighborhood}}<div data-section-id="LOCATION_DEFAULT">
+  <h2>Where you'll be</h2>
+  <h3>{{.Neighborhood}}, {{.Location}}</h3>
+</div>
+{{end}}{{with .Host}}<div data-section-id="HOST_OVERVIEW_DEFAULT">
+  <a href="/users/show/{{.ID}}"><h2>Hosted by {{.Name}}</h2></a>
+  {{if .Superhost}}<span>Superhost</span>{{end}}{{if .Professional}}<span>Professional host</span>{{end}}
+  <span>{{.YearsHosting}} years hosting</span>
+</div>
+<div data-section-id="MEET_YOUR_HOST">
+  <div>Response rate: {{.ResponseRate}}%</div>
+  <div>Responds {{.ResponseTime}}</div>
+</div>
+{{end}}{{if .AmenityGroups}}<div data-section-id="AMENITIES_DEFAULT">
+  <h2>What this place offers</h2>
+  {{range .AmenityPreview}}<div>{{.}}</div>{{end}}
+  <button type="button" onclick="setTimeout(() => { document.getElementById('amenities-modal').hidden = false; }, 100)">Show all {{.AmenityCount}} amenities</button>
+</div>
+<div role="dialog" id="amenities-modal" hidden>
+{{range .AmenityGroups}}  <section><h3>{{.Title}}</h3><ul>{{range .Items}}<li><div id="pdp_v3_{{.ID}}-row-title">{{if .Unavailable}}<del>{{.Name}}</del>{{else}}{{.Name}}{{end}}</div></li>{{end}}</ul></section>
+{{end}}</div>
+{{end}}{{with .Policies}}<div data-section-id="POLICIES_DEFAULT">
+  <h2>Things to know</h2>
+{{range .}}  <div>
+    <h3>{{.Title}}</h3>
+{{range .Preview}}    <div>{{.}}</div>
+{{end}}    <button type="button" onclick="setTimeout(() => { document.getElementById('{{.ID}}').hidden = false; }, 100)">Show more</button>
+  </div>
+{{end}}</div>
+{{range .}}<div role="dialog" id="{{.ID}}" hidden>
+  <button type="button" aria-label="Close" onclick="document.getElementById('{{.ID}}').hidden = true">✕</button>
+  <h2>{{.Title}}</h2>
+{{range .Lines}}  <div>{{.}}</div>
+{{end}}</div>
+{{end}}{{end}}{{with .Calendar}}<div data-section-id="AVAILABILITY_CALENDAR_INLINE">
+  <h2>Select check-in date</h2>
+  <button type="button" aria-label="Move forward to switch to the next month."
+    onclick="const months = Array.from(document.querySelectorAll('[data-calendar-month]')); const first = months.findIndex(m => !m.hidden); if (first + 2 < months.length) { months[first].hidden = true; months[first + 2].hidden = false; } this.disabled = first + 3 >= months.length;">Next</button>
+{{range .}}  <div data-calendar-month{{if .Hidden}} hidden{{end}}>
+    <h3>{{.Title}}</h3>
+{{range .Days}}    <div role="button" data-testid="calendar-day-{{.TestID}}" data-is-day-blocked="{{.Blocked}}" aria-label="{{.Label}}">{{.Day}}</div>
+{{end}}  </div>
+{{end}}</div>
+{{end}}{{with .ReviewSection}}{{$host := .HostName}}<div data-section-id="REVIEWS_DEFAULT">
+  <h2>{{.Count}} reviews</h2>
+{{range .Scores}}  <div><div>{{.Label}}</div><div>{{printf "%.1f" .Value}}</div></div>
+{{end}}  <button type="button" onclick="document.getElementById('reviews-modal').hidden = false">Show all {{.Count}} reviews</button>
+</div>
+<div role="dialog" id="reviews-modal" hidden>
+  <div data-testid="pdp-reviews-modal-scrollable-panel" style="max-height: 300px; overflow-y: auto"
+    onscroll="if (this.scrollTop + this.clientHeight >= this.scrollHeight - 20) Array.from(this.querySelectorAll('[data-review-id][hidden]')).slice(0, 5).forEach(r => { r.hidden = false; })">
+{{range .Items}}    <div data-review-id="{{.ID}}" style="min-height: 120px"{{if .Hidden}} hidden{{end}}>
+      <h2>{{.Name}}</h2>
+      <span aria-label="Rating, {{.Stars}} stars">{{.Stars}}★</span>
+      <div>{{.Month}} · Stayed a few nights</div>
+{{if .Translated}}      <div>Translated from Spanish</div>
+{{end}}      <span lang="en">{{.Text}}</span>
+{{if .Response}}      <div>Response from {{$host}}</div>
+      <div>{{.Month}}</div>
+      <span>{{.Response}}</span>
+{{end}}    </div>
+{{end}}  </div>
+</div>
+{{end}}</body></html>
+`))
+}
+
+type sectionLink struct {
+	Href string
+	City string
+}
+
+func (s *Server) serveHome(w http.ResponseWriter) {
+	links := make([]sectionLink, s.opts.Sections)
+	for i := range links {
+		links[i] = sectionLink{Href: sectionPath(i + 1), City: cityName(i + 1)}
+	}
+	render(w, "home", links)
+}
+
+type card struct {
+	ID        string
+	Title     string
+	Nightly   int
+	Rating    float64
+	Reviews   int
+	Thumbnail string
+}
+
+func (s *Server) serveSearch(w http.ResponseWriter, section, page int) {
+	data := struct {
+		City  string
+		Cards []card
+		Next  string
+	}{City: cityName(section)}
+
+	for c := 1; c <= s.opts.CardsPerPage; c++ {
+		r := s.rooms[newRoom(section, page, c).id]
+		data.Cards = append(data.Cards, card{
+			ID:        r.id,
+			Title:     r.title,
+			Nightly:   r.nightly,
+			Rating:    r.rating,
+			Reviews:   r.reviews,
+			Thumbnail: r.photos()[0].path,
+		})
+	}
+	if page < s.opts.PagesPerSection {
+		data.Next = fmt.Sprintf("%s&pagination=%d", sectionPath(section), page+1)
+	}
+	render(w, "search", data)
+}
+
+func (s *Server) serveRoom(w http.ResponseWriter, r room) {
+	ld := map[string]interface{}{
+		"@context": "https://schema.org",
+		"@type":    "VacationRental",
+	}
+	data := struct {
+		JSONLD       template.JS
+		Title        string
+		Location     string
+		Neighborhood string
+		Booking      *booking
+		Reviews      int
+		Description  string
+		Kind         string
+		Overview     []string
+
+		Host           *models.Host
+		AmenityGroups  []amenityGroup
+		AmenityPreview []string
+		AmenityCount   int
+
+		ReviewSection *reviewsSection
+		Calendar      []calendarMonth
+		PhotoTour     *photoTour
+		Policies      []policyBlock
+	}{}
+
+	if !s.missing["title"] {
+		ld["name"] = r.title
+		data.Title = r.title
+	}
+	if !s.missing["location"] {
+		ld["address"] = map[string]string{
+			"@type":           "PostalAddress",
+			"addressLocality": cityName(r.section),
+			"addressRegion":   regionName,
+			"addressCountry":  countryName,
+		}
+		ld["latitude"], ld["longitude"] = r.coordinates()
+		data.Location = r.location()
+		data.Neighborhood = r.neighborhood()
+	}
+	rating := map[string]interface{}{"@type": "AggregateRating"}
+	if !s.missing["rating"] {
+		rating["ratingValue"] = r.rating
+	}
+	if !s.missing["review_count"] {
+		rating["reviewCount"] = r.reviews
+		data.Reviews = r.reviews
+	}
+	if len(rating) > 1 {
+		ld["aggregateRating"] = rating
+	}
+	if !s.missing["price"] {
+		data.Booking = r.booking()
+	}
+	if !s.missing["description"] {
+		ld["description"] = r.description
+		data.Description = r.description
+	}
+	if !s.missing["capacity"] {
+		ld["containsPlace"] = map[string]interface{}{
+			"@type":     "Accommodation",
+			"occupancy": map[string]interface{}{"@type": "QuantitativeValue", "value": r.guests},
+		}
+		data.Kind = r.kind.heading
+		data.Overview = r.overview()
+	}
+	if !s.missing["host"] {
+		h := r.host.listingHost()
+		data.Host = &h
+	}
+	if !s.missing["amenities"] {
+		data.AmenityGroups = amenityGroups(r.amenities())
+		data.AmenityCount = len(r.amenities())
+		for _, a := range r.amenities()[:3] {
+			data.AmenityPreview = append(data.AmenityPreview, a.Name)
+		}
+	}
+
+	if !s.missing["reviews"] {
+		data.ReviewSection = r.reviewsSection()
+	}
+	if !s.missing["calendar"] {
+		data.Calendar = r.calendarMonths()
+	}
+	if !s.missing["policies"] {
+		data.Policies = r.policyBlocks()
+	}
+	if !s.missing["photos"] {
+		data.PhotoTour = r.photoTour()
+		var images []string
+		for _, p := range data.PhotoTour.Hero {
+			images = append(images, s.URL+p.Src)
+		}
+		ld["image"] = images
+	}
+
+	b, err := json.Marshal(ld)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data.JSONLD = template.JS(b)
+	render(w, "room", data)
+}
+
+func (s *Server) serveProfile(w http.ResponseWriter, h *host) {
+	render(w, "profile", h.listingHost())
+}
+
+// booking is the booking panel: the headline total and its breakdown.
+type booking struct {
+	CheckIn        string // as the date picker shows it, "11/2/2026"
+	CheckOut       string
+	Nights         int
+	Nightly        int
+	Subtotal       int
+	Cleaning       int
+	Service        int
+	Discount       int
+	Taxes          int
+	Total          int // before taxes
+	TotalWithTaxes int
+}
+
+func (r room) booking() *booking {
+	b := &booking{
+		CheckIn:  checkIn.Format("1/2/2006"),
+		CheckOut: r.checkOut().Format("1/2/2006"),
+		Nights:   r.nights,
+		Nightly:  r.nightly,
+		Subtotal: r.subtotal(),
+		Cleaning: r.cleaning,
+		Service:  r.serviceFee(),
+		Discount: r.discount(),
+		Total:    r.total(),
+	}
+	if r.taxed {
+		b.Taxes = r.taxes()
+		b.TotalWithTaxes = r.total() + b.Taxes
+	}
+	return b
+}
+
+// reviewsSection is the reviews summary and the "Show all reviews"
+// modal, which reveals reviewsPerScroll more reviews each time it is
+// scrolled to the bottom.
+type reviewsSection struct {
+	Count    int
+	Scores   []scoreRow
+	Items    []reviewItem
+	HostName string
+}
+
+type scoreRow struct {
+	Label string
+	Value float64
+}
+
+type reviewItem struct {
+	ID         string
+	Name       string
+	Month      string // "September 2026"
+	Stars      int
+	Translated bool
+	Text       string
+	Response   string
+	Hidden     bool
+}
+
+const reviewsPerScroll = 5
+
+func (r room) reviewsSection() *reviewsSection {
+	sc := r.scores()
+	sec := &reviewsSection{
+		Count: r.reviews,
+		Scores: []scoreRow{
+			{"Cleanliness", sc.Cleanliness},
+			{"Accuracy", sc.Accuracy},
+			{"Check-in", sc.CheckIn},
+			{"Communication", sc.Communication},
+			{"Location", sc.Location},
+			{"Value", sc.Value},
+		},
+		HostName: r.host.name,
+	}
+	for i, rv := range r.guestReviews() {
+		date, _ := time.Parse("2006-01-02", rv.Date)
+		sec.Items = append(sec.Items, reviewItem{
+			ID:         rv.ID,
+			Name:       rv.ReviewerName,
+			Month:      date.Format("January 2006"),
+			Stars:      rv.Rating,
+			Translated: rv.Language != "en",
+			Text:       rv.Text,
+			Response:   rv.HostResponse,
+			Hidden:     i >= reviewsPerScroll,
+		})
+	}
+	return sec
+}
+
+// calendarMonth is one month of the availability calendar. Two months
+// show at a time; "Next" moves on by one.
+type calendarMonth struct {
+	Title  string // "November 2026"
+	Hidden bool
+	Days   []calendarCell
+}
+
+type calendarCell struct {
+	TestID  string // "11/02/2026"
+	Day     int
+	Blocked bool
+	Label   string
+}
+
+// shownCalendarMonths covers twelve months from today, which ends in the
+// thirteenth calendar month.
+const shownCalendarMonths = 13
+
+func (r room) calendarMonths() []calendarMonth {
+	today := mockToday()
+	first := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	var months []calendarMonth
+	for i := 0; i < shownCalendarMonths; i++ {
+		start := first.AddDate(0, i, 0)
+		m := calendarMonth{Title: start.Format("January 2006"), Hidden: i >= 2}
+		for day := start; day.Month() == start.Month(); day = day.AddDate(0, 0, 1) {
+			d := r.calendarDay(day, today)
+			label := day.Format("2, Monday, January 2006") + ". Unavailable."
+			if d.Available {
+				label = fmt.Sprintf("%s. Available. %d night minimum. Select as check-in date.", day.Format("2, Monday, January 2006"), d.MinNights)
+			}
+			m.Days = append(m.Days, calendarCell{TestID: day.Format("01/02/2006"), Day: day.Day(), Blocked: !d.Available, Label: label})
+		}
+		months = append(months, m)
+	}
+	return months
+}
+
+// photoTour is the hero images and the "Show all photos" tour, which
+// groups photos by room; untagged photos come last under "Additional
+// photos". Photos without a caption get Airbnb's placeholder alt text.
+type photoTour struct {
+	Count int
+	Hero  []photoItem
+	Rooms []photoRoom
+}
+
+type photoRoom struct {
+	Title  string
+	Photos []photoItem
+}
+
+type photoItem struct {
+	Src string
+	Alt string
+}
+
+const heroPhotos = 5
+
+func (r room) photoTour() *photoTour {
+	t := &photoTour{}
+	for i, p := range r.photos() {
+		item := photoItem{Src: p.path, Alt: p.caption}
+		if item.Alt == "" {
+			item.Alt = fmt.Sprintf("Listing image %d", i+1)
+		}
+		title := p.room
+		if title == "" {
+			title = "Additional photos"
+		}
+		if len(t.Rooms) == 0 || t.Rooms[len(t.Rooms)-1].Title != title {
+			t.Rooms = append(t.Rooms, photoRoom{Title: title})
+		}
+		t.Rooms[len(t.Rooms)-1].Photos = append(t.Rooms[len(t.Rooms)-1].Photos, item)
+		if i < heroPhotos {
+			t.Hero = append(t.Hero, item)
+		}
+		t.Count++
+	}
+	return t
+}
+
+// policyBlock is one part of "Things to know": a few lines in the
+// section and all of them in its "Show more" modal.
+type policyBlock struct {
+	ID      string
+	Title   string
+	Preview []string
+	Lines   []string
+}
+
+func (r room) policyBlocks() []policyBlock {
+	rules, safety := r.houseRules(), r.safetyItems()
+	cancellation := []string{r.cancellationText()}
+	return []policyBlock{
+		{"house-rules-modal", "House rules", rules[:3], rules},
+		{"safety-modal", "Safety & property", safety[:2], safety},
+		{"cancellation-modal", "Cancellation policy", cancellation, append(cancellation, "Review this Host's full policy for details.")},
+	}
+}
+
+type amenityGroup struct {
+	Title string
+	Items []amenityItem
+}
+
+type amenityItem struct {
+	ID          string
+	Name        string
+	Unavailable bool
+}
+
+// amenityGroups arranges amenities the way the "Show all amenities"
+// modal does: one group per category, unavailable ones last under "Not
+// included".
+func amenityGroups(amenities []models.Amenity) []amenityGroup {
+	var groups []amenityGroup
+	var notIncluded []amenityItem
+	for i, a := range amenities {
+		item := amenityItem{ID: fmt.Sprintf("amenity_%d", i), Name: a.Name, Unavailable: !a.Available}
+		if !a.Available {
+			notIncluded = append(notIncluded, item)
+			continue
+		}
+		if len(groups) == 0 || groups[len(groups)-1].Title != a.Category {
+			groups = append(groups, amenityGroup{Title: a.Category})
+		}
+		groups[len(groups)-1].Items = append(groups[len(groups)-1].Items, item)
+	}
+	if len(notIncluded) > 0 {
+		groups = append(groups, amenityGroup{Title: "Not included", Items: notIncluded})
+	}
+	return groups
+}
+
+func render(w http.ResponseWriter, name string, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := pages.ExecuteTemplate(w, name, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
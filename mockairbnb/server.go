@@ -0,0 +1,821 @@
+// Package mockairbnb is a fake Airbnb site for end-to-end tests of the
+// scraper without internet access. It serves a homepage with /s/.../homes
+// section links, paginated search pages with listing cards and a "Next"
+// link, /rooms/<id> detail pages and their /im/pictures/ photos, all
+// deterministic so a run can be checked against Server.Listings.
+//
+// Point config.BaseURL at Server.URL:
+//
+//	srv := mockairbnb.Start(mockairbnb.Options{RateLimitFirst: 1})
+//	defer srv.Close()
+//	cfg.BaseURL = srv.URL + "/"
+package mockairbnb
+
+import (
+	"airbnb-scraper/models"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options shape the fake site. Zero values give a small, well-behaved
+// site: 2 sections × 2 results pages × 4 cards, no latency, no errors.
+type Options struct {
+	Sections        int // section links on the homepage
+	PagesPerSection int // results pages per section, linked via "Next"
+	CardsPerPage    int // listing cards per results page
+
+	// Latency is added to every response.
+	Latency time.Duration
+
+	// RateLimitFirst makes each detail page answer 429 Too Many Requests
+	// this many times before it serves the listing, to exercise retries.
+	RateLimitFirst int
+
+	// ForbiddenEvery makes every Nth room (1-based, in crawl order) answer
+	// 403 Forbidden on every request. 0 disables it.
+	ForbiddenEvery int
+
+	// MissingFields are left out of every detail page, both from the
+	// JSON-LD and the DOM: any of MissingFieldNames.
+	MissingFields []string
+}
+
+// MissingFieldNames lists the names Options.MissingFields accepts.
+// "capacity" drops the whole overview (room type through bathrooms);
+// "price" drops the whole booking panel, dates and breakdown included;
+// "reviews" drops the reviews section, category scores and modal;
+// "calendar" drops the availability calendar; "photos" drops the photos
+// and the photo tour; "policies" drops the "Things to know" section.
+var MissingFieldNames = []string{"title", "price", "location", "rating", "review_count", "description", "capacity", "amenities", "host", "reviews", "calendar", "photos", "policies"}
+
+func (o Options) withDefaults() Options {
+	if o.Sections <= 0 {
+		o.Sections = 2
+	}
+	if o.PagesPerSection <= 0 {
+		o.PagesPerSection = 2
+	}
+	if o.CardsPerPage <= 0 {
+		o.CardsPerPage = 4
+	}
+	return o
+}
+
+// Server is the fake site. It is an http.Handler; Start also runs it on
+// a local port.
+type Server struct {
+	URL string // set by Start, e.g. "http://127.0.0.1:41234"
+
+	opts    Options
+	missing map[string]bool
+	rooms   map[string]room // by room ID
+	order   []string        // room IDs in crawl order
+
+	ts *httptest.Server
+
+	mu   sync.Mutex
+	hits map[string]int // by request path
+}
+
+// New builds the fake site without starting a listener.
+func New(opts Options) (*Server, error) {
+	opts = opts.withDefaults()
+
+	s := &Server{
+		opts:    opts,
+		missing: make(map[string]bool),
+		rooms:   make(map[string]room),
+		hits:    make(map[string]int),
+	}
+	for _, f := range opts.MissingFields {
+		f = strings.TrimSpace(f)
+		if !validField(f) {
+			return nil, fmt.Errorf("unknown field %q (want one of %s)", f, strings.Join(MissingFieldNames, ", "))
+		}
+		s.missing[f] = true
+	}
+
+	for section := 1; section <= opts.Sections; section++ {
+		for page := 1; page <= opts.PagesPerSection; page++ {
+			for card := 1; card <= opts.CardsPerPage; card++ {
+				r := newRoom(section, page, card)
+				r.forbidden = opts.ForbiddenEvery > 0 && (len(s.order)+1)%opts.ForbiddenEvery == 0
+				s.rooms[r.id] = r
+				s.order = append(s.order, r.id)
+			}
+		}
+	}
+	return s, nil
+}
+
+// Start builds the fake site and serves it on a random local port.
+// It panics on invalid options, like httptest.NewServer does on failure.
+func Start(opts Options) *Server {
+	s, err := New(opts)
+	if err != nil {
+		panic("mockairbnb: " + err.Error())
+	}
+	s.ts = httptest.NewServer(s)
+	s.URL = s.ts.URL
+	return s
+}
+
+// Close stops a server started with Start.
+func (s *Server) Close() {
+	if s.ts != nil {
+		s.ts.Close()
+	}
+}
+
+// Hits returns how many requests were made for path, e.g. "/rooms/41001101".
+func (s *Server) Hits(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.hits[path]
+}
+
+// Listings returns what a complete scrape of the site should produce:
+// one listing per reachable room (forbidden rooms and rooms whose title
+// is missing are left out), with missing fields zeroed. URLs are relative
+// to Server.URL, which must be set. Host listing counts are only on the
+// profile pages, so they assume host_profiles. Reviews and calendars
+// depend on the scraper's max_reviews and calendar_months and are left
+// out; see Server.Reviews and Server.Calendar. Photos have no Hash or
+// File, which depend on photos_dir.
+func (s *Server) Listings() []models.Listing {
+	var out []models.Listing
+	if s.missing["title"] {
+		return out
+	}
+	for _, id := range s.order {
+		r := s.rooms[id]
+		if r.forbidden {
+			continue
+		}
+		roomID, _ := strconv.ParseInt(r.id, 10, 64)
+		l := models.Listing{
+			ID:       roomID,
+			Platform: "airbnb",
+			Title:    r.title,
+			URL:      s.URL + "/rooms/" + r.id,
+		}
+		if !s.missing["price"] {
+			l.RawPrice = fmt.Sprintf("$%d", r.total())
+			l.Price = float64(r.total())
+			l.CheckIn = checkIn.Format("2006-01-02")
+			l.CheckOut = r.checkOut().Format("2006-01-02")
+			l.Nights = r.nights
+			l.NightlyRate = float64(r.nightly)
+			l.CleaningFee = float64(r.cleaning)
+			l.ServiceFee = float64(r.serviceFee())
+			l.Discount = float64(r.discount())
+			if r.taxed {
+				l.Taxes = float64(r.taxes())
+			}
+			l.TotalPrice = float64(r.total())
+			l.PricePerNight = math.Round(float64(r.total())/float64(r.nights)*100) / 100
+		}
+		if !s.missing["location"] {
+			l.Location = r.location()
+			l.Latitude, l.Longitude = r.coordinates()
+			l.Neighborhood = r.neighborhood()
+			l.City = cityName(r.section)
+			l.Region = regionName
+			l.Country = countryName
+		}
+		if !s.missing["rating"] {
+			l.Rating = r.rating
+		}
+		if !s.missing["review_count"] {
+			l.ReviewCount = r.reviews
+		}
+		if !s.missing["description"] {
+			l.Description = r.description
+		}
+		if !s.missing["capacity"] && !s.missing["location"] {
+			// Room and property type are only on the "<kind> in <location>" heading.
+			l.RoomType = r.kind.roomType
+			l.PropertyType = r.kind.propertyType
+		}
+		if !s.missing["capacity"] {
+			l.Guests = r.guests
+			l.Bedrooms = r.bedrooms
+			l.Beds = r.beds
+			l.Bathrooms = r.bath.count
+			l.SharedBath = r.bath.shared
+		}
+		if !s.missing["amenities"] {
+			l.Amenities = r.amenities()
+		}
+		if !s.missing["host"] {
+			l.Host = r.host.listingHost()
+		}
+		if !s.missing["reviews"] {
+			l.Scores = r.scores()
+		}
+		if !s.missing["policies"] {
+			l.Policies = r.policies()
+			if s.missing["price"] && l.Policies.Cancellation != models.CancellationStrict && l.Policies.Cancellation != models.CancellationNonRefundable {
+				// Those two are told apart by date, which needs the stay dates.
+				l.Policies.Cancellation = ""
+			}
+		}
+		if !s.missing["photos"] {
+			for _, p := range r.photos() {
+				l.Photos = append(l.Photos, models.Photo{URL: s.URL + p.path, Caption: p.caption, Room: p.room})
+			}
+			l.Thumbnail = l.Photos[0].URL
+		}
+		out = append(out, l)
+	}
+	return out
+}
+
+// CardListings returns what a cards-only scrape of the site should
+// produce: one listing per room, built from its search results card.
+// Cards are never affected by Options, so every room is there.
+func (s *Server) CardListings() []models.Listing {
+	var out []models.Listing
+	for _, id := range s.order {
+		r := s.rooms[id]
+		roomID, _ := strconv.ParseInt(r.id, 10, 64)
+		out = append(out, models.Listing{
+			ID:            roomID,
+			Platform:      "airbnb",
+			Title:         r.title,
+			URL:           s.URL + "/rooms/" + r.id,
+			RawPrice:      fmt.Sprintf("$%d", r.nightly),
+			Price:         float64(r.nightly),
+			PricePerNight: float64(r.nightly),
+			Rating:        r.rating,
+			ReviewCount:   r.reviews,
+			Thumbnail:     s.URL + r.photos()[0].path,
+		})
+	}
+	return out
+}
+
+// Reviews returns every review the reviews modal of a room holds, newest
+// first; a scrape with max_reviews N should find the first N. Relative
+// dates are not used, so Date is exact.
+func (s *Server) Reviews(roomID int64) []models.Review {
+	r, ok := s.rooms[strconv.FormatInt(roomID, 10)]
+	if !ok || s.missing["reviews"] {
+		return nil
+	}
+	return r.guestReviews()
+}
+
+// Calendar returns the calendar days a scrape with calendar_months months
+// run today should find for a room: today up to the same day months
+// months later.
+func (s *Server) Calendar(roomID int64, months int) []models.CalendarDay {
+	r, ok := s.rooms[strconv.FormatInt(roomID, 10)]
+	if !ok || s.missing["calendar"] {
+		return nil
+	}
+	today := mockToday()
+	var out []models.CalendarDay
+	for day := today; day.Before(today.AddDate(0, months, 0)); day = day.AddDate(0, 0, 1) {
+		out = append(out, r.calendarDay(day, today))
+	}
+	return out
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.hits[r.URL.Path]++
+	hits := s.hits[r.URL.Path]
+	s.mu.Unlock()
+
+	if s.opts.Latency > 0 {
+		select {
+		case <-time.After(s.opts.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	switch path := r.URL.Path; {
+	case path == "/":
+		s.serveHome(w)
+
+	case strings.HasPrefix(path, "/s/") && strings.HasSuffix(path, "/homes"):
+		section, _ := strconv.Atoi(r.URL.Query().Get("section"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("pagination"))
+		if page == 0 {
+			page = 1
+		}
+		if section < 1 || section > s.opts.Sections || page < 1 || page > s.opts.PagesPerSection {
+			http.NotFound(w, r)
+			return
+		}
+		s.serveSearch(w, section, page)
+
+	case strings.HasPrefix(path, "/users/show/"):
+		id, _ := strconv.ParseInt(strings.TrimPrefix(path, "/users/show/"), 10, 64)
+		h := hostByID(id)
+		if h == nil {
+			http.NotFound(w, r)
+			return
+		}
+		s.serveProfile(w, h)
+
+	case strings.HasPrefix(path, "/im/pictures/"):
+		roomID, _, _ := strings.Cut(strings.TrimPrefix(path, "/im/pictures/"), "/")
+		room, ok := s.rooms[roomID]
+		if !ok || s.missing["photos"] {
+			http.NotFound(w, r)
+			return
+		}
+		for _, p := range room.photos() {
+			if p.path == path {
+				w.Header().Set("Content-Type", "image/jpeg")
+				w.Write(photoImage(p.image))
+				return
+			}
+		}
+		http.NotFound(w, r)
+
+	case strings.HasPrefix(path, "/rooms/"):
+		room, ok := s.rooms[strings.TrimPrefix(path, "/rooms/")]
+		switch {
+		case !ok:
+			http.NotFound(w, r)
+		case room.forbidden:
+			http.Error(w, "Forbidden", http.StatusForbidden)
+		case hits <= s.opts.RateLimitFirst:
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+		default:
+			s.serveRoom(w, room)
+		}
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func validField(name string) bool {
+	for _, f := range MissingFieldNames {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// room is one fake listing. Every value is derived from its position so
+// runs are reproducible.
+type room struct {
+	id          string
+	section     int
+	title       string
+	nightly     int
+	nights      int
+	cleaning    int
+	taxed       bool // the breakdown lists taxes and a total including them
+	rating      float64
+	reviews     int
+	description string
+	forbidden   bool
+
+	host     *host
+	kind     roomKind
+	guests   int
+	bedrooms int // 0 is a studio
+	beds     int
+	bath     bathroom
+}
+
+type roomKind struct {
+	heading      string // as shown on the page, before " in <location>"
+	roomType     string
+	propertyType string
+}
+
+var roomKinds = []roomKind{
+	{"Entire rental unit", "Entire home/apt", "rental unit"},
+	{"Entire condo", "Entire home/apt", "condo"},
+	{"Private room in home", "Private room", "home"},
+	{"Entire cottage", "Entire home/apt", "cottage"},
+}
+
+type bathroom struct {
+	label  string
+	count  float64
+	shared bool
+}
+
+// bathrooms by number of bedrooms (0-3).
+var bathrooms = []bathroom{
+	{"1 bath", 1, false},
+	{"1 bath", 1, false},
+	{"1.5 baths", 1.5, false},
+	{"2.5 baths", 2.5, false},
+}
+
+func newRoom(section, page, card int) room {
+	n := section*100 + page*10 + card
+	r := room{
+		id:          strconv.Itoa(41000000 + section*1000 + page*100 + card),
+		section:     section,
+		title:       fmt.Sprintf("Mock stay %d-%d-%d", section, page, card),
+		nightly:     40 + (n*37)%260,
+		nights:      2,
+		cleaning:    15 + (n*7)%40,
+		taxed:       n%2 == 0,
+		rating:      float64(400+(n*13)%101) / 100,
+		reviews:     3 + (n*53)%900,
+		description: fmt.Sprintf("A quiet mock apartment in %s, card %d of results page %d.", cityName(section), card, page),
+		host:        hosts[min(n%4, len(hosts)-1)], // half the rooms are the company's
+		kind:        roomKinds[n%len(roomKinds)],
+		bedrooms:    n % 4,
+	}
+	if n%7 == 3 {
+		r.nights = 7 // gets the weekly discount
+	}
+	r.bath = bathrooms[r.bedrooms]
+	r.beds = max(1, r.bedrooms)
+	r.guests = 2 * r.beds
+	if r.kind.roomType == "Private room" {
+		r.bedrooms, r.beds, r.guests = 1, 1, 2
+		r.bath = bathroom{"1 shared bath", 1, true}
+	}
+	return r
+}
+
+// host is a fake host. Rooms are shared between a few of them; the
+// company is labelled "Professional host" like Airbnb does for businesses.
+type host struct {
+	id           int64
+	name         string
+	superhost    bool
+	years        int
+	responseRate int
+	responseTime string
+	listings     int // shown on the profile page
+	professional bool
+}
+
+var hosts = []*host{
+	{id: 9001, name: "Aisha", superhost: true, years: 6, responseRate: 100, responseTime: "within an hour", listings: 2},
+	{id: 9002, name: "Ben", years: 2, responseRate: 80, responseTime: "within a day", listings: 1},
+	{id: 9003, name: "Mockland Stays Sdn Bhd", years: 9, responseRate: 99, responseTime: "within an hour", listings: 34, professional: true},
+}
+
+// hostByID returns the fake host with the given ID, or nil.
+func hostByID(id int64) *host {
+	for _, h := range hosts {
+		if h.id == id {
+			return h
+		}
+	}
+	return nil
+}
+
+func (h *host) listingHost() models.Host {
+	return models.Host{
+		ID:           h.id,
+		Name:         h.name,
+		Superhost:    h.superhost,
+		YearsHosting: h.years,
+		ResponseRate: h.responseRate,
+		ResponseTime: h.responseTime,
+		ListingCount: h.listings,
+		Professional: h.professional,
+	}
+}
+
+// amenityCatalog is every amenity a mock room can have, by category.
+var amenityCatalog = []struct {
+	category string
+	names    []string
+}{
+	{"Bathroom", []string{"Hair dryer", "Shampoo"}},
+	{"Kitchen and dining", []string{"Kitchen", "Microwave"}},
+	{"Internet and office", []string{"Wifi", "Dedicated workspace"}},
+	{"Heating and cooling", []string{"Air conditioning"}},
+	{"Parking and facilities", []string{"Free parking on premises", "Pool"}},
+}
+
+// amenities returns the room's amenities as the scraper should read
+// them: available ones under their category, then unavailable ones
+// (shown under "Not included", which has no category). Each amenity is
+// missing from about one room in five, and only one room in three has a
+// pool.
+func (r room) amenities() []models.Amenity {
+	n, _ := strconv.Atoi(r.id)
+	var offered, missing []models.Amenity
+	i := 0
+	for _, group := range amenityCatalog {
+		for _, name := range group.names {
+			i++
+			has := (n+3*i)%5 != 0
+			if name == "Pool" {
+				has = n%3 == 0
+			}
+			if has {
+				offered = append(offered, models.Amenity{Name: name, Category: group.category, Available: true})
+			} else {
+				missing = append(missing, models.Amenity{Name: name})
+			}
+		}
+	}
+	return append(offered, missing...)
+}
+
+// scores are the room's category ratings, spread around its rating.
+func (r room) scores() models.CategoryScores {
+	score := func(offset float64) float64 {
+		return math.Min(5, math.Round((r.rating+offset)*10)/10)
+	}
+	return models.CategoryScores{
+		Cleanliness:   score(0.1),
+		Accuracy:      score(0),
+		CheckIn:       score(0.1),
+		Communication: score(0.2),
+		Location:      score(-0.1),
+		Value:         score(-0.2),
+	}
+}
+
+// maxGuestReviews caps the reviews a mock room serves; the rest of its
+// review count exists only as a number.
+const maxGuestReviews = 24
+
+var reviewerNames = []string{"Maya", "Tom", "Lucía", "Kenji", "Priya", "Omar", "Hannah"}
+
+// guestReviews are the room's reviews, newest first, one per month
+// going back from September 2026. Every fourth was written in Spanish
+// and is shown translated; every third has a host response.
+func (r room) guestReviews() []models.Review {
+	n, _ := strconv.Atoi(r.id)
+	latest := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+	var out []models.Review
+	for i := 0; i < min(r.reviews, maxGuestReviews); i++ {
+		name := reviewerNames[(n+i)%len(reviewerNames)]
+		rv := models.Review{
+			ID:           fmt.Sprintf("%s%02d", r.id, i),
+			ReviewerName: name,
+			Date:         latest.AddDate(0, -i, 0).Format("2006-01-02"),
+			Language:     "en",
+			Rating:       5 - i%3/2,
+			Text:         fmt.Sprintf("Review %d of %s: a comfortable stay in %s, exactly as described, and we would book it again.", i+1, r.title, cityName(r.section)),
+		}
+		if i%4 == 3 {
+			rv.Language = "es"
+		}
+		if i%3 == 0 {
+			rv.HostResponse = fmt.Sprintf("Thank you, %s! You are welcome back any time.", name)
+		}
+		out = append(out, rv)
+	}
+	return out
+}
+
+// mockToday is the current date; calendars run from its month on.
+func mockToday() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// calendarWindowDays is how far ahead every third room takes bookings;
+// the days after it are blocked.
+const calendarWindowDays = 90
+
+// calendarDay is the room's calendar entry for day. Between 30% and 70%
+// of the days are booked depending on the room, days before today are
+// blocked, and weekends need one night more.
+func (r room) calendarDay(day, today time.Time) models.CalendarDay {
+	n, _ := strconv.Atoi(r.id)
+	k := int(day.Unix() / 86400)
+	d := models.CalendarDay{
+		Date:      day.Format("2006-01-02"),
+		Available: (k*7+n)%10 >= 3+n%5,
+		MinNights: 1 + n%3,
+	}
+	if (n%3 == 1 && !day.Before(today.AddDate(0, 0, calendarWindowDays))) || day.Before(today) {
+		d.Available = false
+	}
+	if wd := day.Weekday(); wd == time.Friday || wd == time.Saturday {
+		d.MinNights++
+	}
+	return d
+}
+
+// policies are the room's house rules, safety items and cancellation
+// tier, as the scraper should read them from houseRules, safetyItems and
+// cancellationText.
+func (r room) policies() models.Policies {
+	n, _ := strconv.Atoi(r.id)
+	p := models.Policies{
+		CheckInFrom:     []string{"15:00", "14:00", "16:00"}[n%3],
+		CheckoutBy:      "11:00",
+		MaxGuests:       r.guests,
+		PetsAllowed:     flag(n%4 == 0),
+		SmokingAllowed:  flag(n%9 == 0),
+		PartiesAllowed:  flag(n%10 == 5),
+		SmokeAlarm:      flag(n%5 != 0),
+		COAlarm:         flag(n%3 != 2),
+		SecurityCameras: flag(n%6 == 0),
+		Cancellation:    models.CancellationTiers[n%len(models.CancellationTiers)],
+	}
+	switch n % 3 {
+	case 1:
+		p.CheckInUntil = "22:00"
+	case 2:
+		p.CheckInUntil = "21:00"
+	}
+	if n%2 == 0 {
+		p.CheckoutBy = "10:00"
+	}
+	return p
+}
+
+func flag(v bool) *bool { return &v }
+
+// houseRules are the lines of the house rules modal; the section shows
+// the first three.
+func (r room) houseRules() []string {
+	p := r.policies()
+	checkIn := "Check-in after " + clock(p.CheckInFrom)
+	if p.CheckInUntil != "" {
+		checkIn = fmt.Sprintf("Check-in: %s - %s", clock(p.CheckInFrom), clock(p.CheckInUntil))
+	}
+	rules := []string{checkIn, "Checkout before " + clock(p.CheckoutBy), fmt.Sprintf("%d guests maximum", p.MaxGuests)}
+	rules = append(rules, pick(*p.PetsAllowed, "Pets allowed", "No pets"))
+	rules = append(rules, "Quiet hours 10:00 PM - 7:00 AM")
+	rules = append(rules, pick(*p.PartiesAllowed, "Events allowed", "No parties or events"))
+	rules = append(rules, pick(*p.SmokingAllowed, "Smoking allowed", "No smoking"))
+	return rules
+}
+
+// safetyItems are the lines of the safety & property modal; the section
+// shows the first two. Security cameras are only listed when there are
+// some.
+func (r room) safetyItems() []string {
+	n, _ := strconv.Atoi(r.id)
+	p := r.policies()
+	var items []string
+	if *p.SecurityCameras {
+		items = append(items, "Exterior security cameras on property")
+	}
+	items = append(items,
+		pick(*p.COAlarm, "Carbon monoxide alarm", "Carbon monoxide alarm not reported"),
+		pick(*p.SmokeAlarm, "Smoke alarm", "Smoke alarm not reported"))
+	if n%3 == 0 {
+		items = append(items, "Pool/hot tub without a gate or lock")
+	}
+	return items
+}
+
+// cancellationText words the room's cancellation tier the way Airbnb
+// does, with dates relative to checkIn rather than the policy's name.
+func (r room) cancellationText() string {
+	date := func(days int) string { return checkIn.AddDate(0, 0, -days).Format("Jan 2") }
+	switch r.policies().Cancellation {
+	case models.CancellationFlexible:
+		return fmt.Sprintf("Free cancellation before %s.", date(1))
+	case models.CancellationModerate:
+		return fmt.Sprintf("Free cancellation before %s. Cancel before check-in on %s for a partial refund.", date(5), date(0))
+	case models.CancellationStrict:
+		return fmt.Sprintf("Free cancellation for 48 hours. Cancel before %s for a partial refund.", date(7))
+	default:
+		return "This reservation is non-refundable."
+	}
+}
+
+// clock renders "15:00" as "3:00 PM".
+func clock(hhmm string) string {
+	t, _ := time.Parse("15:04", hhmm)
+	return t.Format("3:04 PM")
+}
+
+func pick(v bool, yes, no string) string {
+	if v {
+		return yes
+	}
+	return no
+}
+
+// photo is one picture of a room's photo tour.
+type photo struct {
+	path    string // under /im/pictures/<room ID>/
+	room    string // photo tour heading, "" for "Additional photos"
+	caption string
+	image   string // what the picture shows; equal images have equal bytes
+}
+
+// photos are the room's photo tour, room by room, untagged photos last.
+// Rooms of a section share their building's exterior photo, and every
+// room with a pool shares the same pool photo, each under its own URL.
+func (r room) photos() []photo {
+	n, _ := strconv.Atoi(r.id)
+	var out []photo
+	add := func(room, caption, image string) {
+		out = append(out, photo{
+			path:    fmt.Sprintf("/im/pictures/%s/%02d.jpg", r.id, len(out)+1),
+			room:    room,
+			caption: caption,
+			image:   image,
+		})
+	}
+
+	add("Living room", "Living room with a sofa bed", r.id+"/living")
+	for b := 1; b <= r.bedrooms; b++ {
+		add(fmt.Sprintf("Bedroom %d", b), fmt.Sprintf("Queen bed in bedroom %d", b), fmt.Sprintf("%s/bedroom-%d", r.id, b))
+	}
+	bath := "Full bathroom"
+	if r.bath.shared {
+		bath = "Shared bathroom"
+	}
+	add(bath, "", r.id+"/bathroom")
+	add("Exterior", "Building entrance", fmt.Sprintf("section-%d/exterior", r.section))
+	if n%3 == 0 {
+		add("Pool", "Rooftop pool", "pool")
+	}
+	add("", "", r.id+"/street")
+	add("", "View from the balcony", r.id+"/view")
+	return out
+}
+
+// photoImage is a tiny stand-in JPEG whose bytes depend only on image.
+func photoImage(image string) []byte {
+	b := []byte{0xFF, 0xD8, 0xFF, 0xFE, 0, byte(len(image) + 2)}
+	b = append(b, image...)
+	return append(b, 0xFF, 0xD9)
+}
+
+// overview is the capacity line under the heading, one item per entry.
+func (r room) overview() []string {
+	bedrooms := "Studio"
+	if r.bedrooms > 0 {
+		bedrooms = plural(r.bedrooms, "bedroom")
+	}
+	return []string{plural(r.guests, "guest"), bedrooms, plural(r.beds, "bed"), r.bath.label}
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// checkIn is the first night of every mock stay; the booking panel
+// always quotes checkIn plus r.nights.
+var checkIn = time.Date(2026, time.November, 2, 0, 0, 0, 0, time.UTC)
+
+func (r room) checkOut() time.Time { return checkIn.AddDate(0, 0, r.nights) }
+
+// The price breakdown: a 14% service fee, 10% off stays of a week or
+// more, and 6% tax shown on every other room. total is before taxes.
+func (r room) subtotal() int   { return r.nightly * r.nights }
+func (r room) serviceFee() int { return r.subtotal() * 14 / 100 }
+func (r room) taxes() int      { return r.total() * 6 / 100 }
+
+func (r room) discount() int {
+	if r.nights >= 7 {
+		return r.subtotal() / 10
+	}
+	return 0
+}
+
+func (r room) total() int {
+	return r.subtotal() + r.cleaning + r.serviceFee() - r.discount()
+}
+
+func (r room) location() string {
+	return cityName(r.section) + ", " + regionName + ", " + countryName
+}
+
+const (
+	regionName  = "Mock State"
+	countryName = "Mockland"
+)
+
+var neighborhoods = []string{"Old Town", "Riverside", "Harbour", "University Park"}
+
+func (r room) neighborhood() string {
+	n, _ := strconv.Atoi(r.id)
+	return neighborhoods[n%len(neighborhoods)]
+}
+
+// coordinates places each section's rooms around their own city center.
+func (r room) coordinates() (float64, float64) {
+	n, _ := strconv.Atoi(r.id)
+	return 3.1 + float64(r.section)*0.1 + float64(n%100)*0.0005, 101.6 + float64(r.section)*0.1 + float64(n%37)*0.0007
+}
+
+func cityName(section int) string { return fmt.Sprintf("Mock City %d", section) }
+
+func sectionPath(section int) string {
+	return fmt.Sprintf("/s/Mock-City-%d--Mockland/homes?section=%d", section, section)
+}
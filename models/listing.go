@@ -1,16 +1,225 @@
 package models
 
+import (
+	"strconv"
+	"strings"
+)
+
 type Listing struct {
-	ID          int
-	Platform    string
-	Title       string
-	Price       float64
-	RawPrice    string
-	Location    string
-	Rating      float64
-	ReviewCount int
-	URL         string
-	Description string
+	// ID is the Airbnb room ID from /rooms/<id>, the listing's identity
+	// (URLs differ per session). Encoded as a JSON string because new
+	// room IDs exceed what JavaScript numbers hold exactly.
+	ID          int64   `json:"id,string,omitempty"`
+	Platform    string  `json:"platform"`
+	Title       string  `json:"title"`
+	Price       float64 `json:"price"`
+	RawPrice    string  `json:"raw_price"`
+	Location    string  `json:"location"`
+	Rating      float64 `json:"rating"`
+	ReviewCount int     `json:"review_count"`
+	URL         string  `json:"url"`
+	Description string  `json:"description"`
+
+	// Price breakdown for the stay the page quoted. Price is the headline
+	// figure as shown, which is a nightly rate on some pages and a total
+	// for Nights nights on others; PricePerNight is the comparable one:
+	// TotalPrice / Nights when the page quoted a stay, otherwise the
+	// nightly rate. TotalPrice is before taxes and after discounts.
+	// Amounts are 0 when the page did not show them.
+	CheckIn       string  `json:"check_in,omitempty"` // YYYY-MM-DD
+	CheckOut      string  `json:"check_out,omitempty"`
+	Nights        int     `json:"nights,omitempty"`
+	NightlyRate   float64 `json:"nightly_rate,omitempty"` // base rate, before fees
+	CleaningFee   float64 `json:"cleaning_fee,omitempty"`
+	ServiceFee    float64 `json:"service_fee,omitempty"`
+	Taxes         float64 `json:"taxes,omitempty"`
+	Discount      float64 `json:"discount,omitempty"` // weekly/monthly and other discounts, as a positive amount
+	TotalPrice    float64 `json:"total_price,omitempty"`
+	PricePerNight float64 `json:"price_per_night,omitempty"`
+
+	// Capacity, from the detail page overview ("Entire rental unit ·
+	// 4 guests · 2 bedrooms · 2 beds · 1.5 shared baths"). RoomType is
+	// one of Airbnb's room types ("Entire home/apt", "Private room",
+	// "Shared room", "Hotel room"); PropertyType is free text ("rental
+	// unit", "condo"). A studio has 0 bedrooms.
+	RoomType     string  `json:"room_type"`
+	PropertyType string  `json:"property_type"`
+	Guests       int     `json:"guests"`
+	Bedrooms     int     `json:"bedrooms"`
+	Beds         int     `json:"beds"`
+	Bathrooms    float64 `json:"bathrooms"`
+	SharedBath   bool    `json:"shared_bath"`
+
+	// Amenities is the full "What this place offers" list, including
+	// amenities the listing marks as unavailable.
+	Amenities []Amenity `json:"amenities,omitempty"`
+
+	// Where the listing is. Location stays the free-text place name;
+	// these are its parts, filled from structured data where the page has
+	// it and otherwise split from Location ("Bukit Bintang, Kuala Lumpur,
+	// Federal Territory of Kuala Lumpur, Malaysia"). Coordinates are 0
+	// when unknown; Airbnb offsets them by a few hundred meters until a
+	// stay is booked.
+	Latitude     float64 `json:"latitude,omitempty"`
+	Longitude    float64 `json:"longitude,omitempty"`
+	Neighborhood string  `json:"neighborhood,omitempty"`
+	City         string  `json:"city,omitempty"`
+	Region       string  `json:"region,omitempty"`
+	Country      string  `json:"country,omitempty"`
+
+	// Host runs the listing; its ID is 0 when the page did not show one.
+	Host Host `json:"host"`
+
+	// Policies are the "Things to know": house rules, safety items and
+	// the cancellation policy.
+	Policies Policies `json:"policies"`
+
+	// Photos are the listing's photo tour, in the order the page shows it.
+	Photos []Photo `json:"photos,omitempty"`
+
+	// Thumbnail is the picture the listing's search results card shows.
+	Thumbnail string `json:"thumbnail,omitempty"`
+
+	// Scores are the per-category ratings of the reviews section. Reviews
+	// are only scraped when the review stage is enabled (max_reviews),
+	// newest first, up to that limit.
+	Scores  CategoryScores `json:"scores"`
+	Reviews []Review       `json:"reviews,omitempty"`
+
+	// Calendar is the availability calendar from the day of the scrape
+	// on, one entry per day for calendar_months months; empty when the
+	// calendar stage is off or the page had no calendar.
+	Calendar []CalendarDay `json:"calendar,omitempty"`
+
+	// Sources records which extraction strategy produced each field,
+	// e.g. {"title": "jsonld", "price": "dom"}. Not persisted.
+	Sources map[string]string `json:"-"`
+}
+
+// Key identifies the listing for deduplication: the room ID when known,
+// otherwise the URL.
+func (l Listing) Key() string {
+	if l.ID != 0 {
+		return strconv.FormatInt(l.ID, 10)
+	}
+	return l.URL
+}
+
+// Amenity is one entry of a listing's amenities list. Category is
+// Airbnb's group heading ("Kitchen and dining", "Parking and
+// facilities"), empty when the source has none.
+type Amenity struct {
+	Name      string `json:"name"`
+	Category  string `json:"category,omitempty"`
+	Available bool   `json:"available"`
+}
+
+// Host is the account behind a listing. Professional marks property
+// managers and companies, as opposed to individuals renting out their
+// own place.
+type Host struct {
+	ID           int64  `json:"id,string,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Superhost    bool   `json:"superhost"`
+	YearsHosting int    `json:"years_hosting,omitempty"`
+	ResponseRate int    `json:"response_rate,omitempty"` // percent
+	ResponseTime string `json:"response_time,omitempty"` // "within an hour"
+	ListingCount int    `json:"listing_count,omitempty"`
+	Professional bool   `json:"professional"`
+}
+
+// Cancellation policy tiers, from most to least lenient. Airbnb's
+// Limited and Firm policies count as strict.
+const (
+	CancellationFlexible      = "flexible"
+	CancellationModerate      = "moderate"
+	CancellationStrict        = "strict"
+	CancellationNonRefundable = "non_refundable"
+)
+
+// CancellationTiers lists the cancellation policy tiers in order of
+// strictness.
+var CancellationTiers = []string{CancellationFlexible, CancellationModerate, CancellationStrict, CancellationNonRefundable}
+
+// Policies are a listing's house rules, safety items and cancellation
+// policy. Times are "15:04"; CheckInUntil is empty when check-in has no
+// end. The flags are nil when the page did not say either way, which is
+// not the same as a rule saying no.
+type Policies struct {
+	CheckInFrom  string `json:"check_in_from,omitempty"`
+	CheckInUntil string `json:"check_in_until,omitempty"`
+	CheckoutBy   string `json:"checkout_by,omitempty"`
+	MaxGuests    int    `json:"max_guests,omitempty"`
+
+	PetsAllowed    *bool `json:"pets_allowed,omitempty"`
+	SmokingAllowed *bool `json:"smoking_allowed,omitempty"`
+	PartiesAllowed *bool `json:"parties_allowed,omitempty"`
+
+	SmokeAlarm      *bool `json:"smoke_alarm,omitempty"`
+	COAlarm         *bool `json:"co_alarm,omitempty"`
+	SecurityCameras *bool `json:"security_cameras,omitempty"`
+
+	Cancellation string `json:"cancellation,omitempty"` // one of CancellationTiers
+}
+
+// Photo is one picture of a listing. Room is the photo tour's room tag
+// ("Bedroom 1", "Pool"), empty for untagged photos. Hash (SHA-256 of the
+// image) and File (its path inside photos_dir) are set once the image is
+// in the photo store.
+type Photo struct {
+	URL     string `json:"url"`
+	Caption string `json:"caption,omitempty"`
+	Room    string `json:"room,omitempty"`
+	Hash    string `json:"hash,omitempty"`
+	File    string `json:"file,omitempty"`
+}
+
+// CategoryScores are Airbnb's per-category ratings (0-5); 0 means the
+// listing does not show that category.
+type CategoryScores struct {
+	Cleanliness   float64 `json:"cleanliness,omitempty"`
+	Accuracy      float64 `json:"accuracy,omitempty"`
+	CheckIn       float64 `json:"check_in,omitempty"`
+	Communication float64 `json:"communication,omitempty"`
+	Location      float64 `json:"location,omitempty"`
+	Value         float64 `json:"value,omitempty"`
+}
+
+// Review is one guest review. Airbnb dates reviews by month ("September
+// 2026") or relative to today ("2 weeks ago"), so Date is approximate:
+// the first of the month, or the day the relative date works out to.
+// Text is as shown, which may be Airbnb's translation; Language is the
+// review's original language as a code ("en", "es") when known.
+type Review struct {
+	ID           string `json:"id,omitempty"`
+	ReviewerName string `json:"reviewer_name"`
+	Date         string `json:"date,omitempty"` // YYYY-MM-DD
+	Language     string `json:"language,omitempty"`
+	Rating       int    `json:"rating,omitempty"` // stars, when the source has them
+	Text         string `json:"text"`
+	HostResponse string `json:"host_response,omitempty"`
+}
+
+// CalendarDay is one day of a listing's availability calendar. Airbnb
+// shows booked nights and nights the host closed the same way, so a
+// blocked day (Available false) may be either. MinNights and Price are 0
+// when the calendar did not show them.
+type CalendarDay struct {
+	Date      string  `json:"date"` // YYYY-MM-DD
+	Available bool    `json:"available"`
+	MinNights int     `json:"min_nights,omitempty"`
+	Price     float64 `json:"price,omitempty"`
+}
+
+// HasAmenity reports whether the listing offers the named amenity
+// (case-insensitive); amenities marked unavailable do not count.
+func (l Listing) HasAmenity(name string) bool {
+	for _, a := range l.Amenities {
+		if a.Available && strings.EqualFold(a.Name, name) {
+			return true
+		}
+	}
+	return false
 }
 
 type ScrapeJob struct {
@@ -22,4 +231,4 @@ type ScrapeResult struct {
 	Listings   []Listing
 	Error      error
 	PageNumber int
-}
\ No newline at end of file
+}
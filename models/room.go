@@ -0,0 +1,26 @@
+package models
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// RoomPathPattern matches the room ID in a listing URL: /rooms/<id> or
+// /rooms/plus/<id>. It is valid both as a Go and as a PostgreSQL regular
+// expression, so stored URLs are read the same way in SQL.
+const RoomPathPattern = `/rooms/(?:plus/)?([0-9]+)`
+
+var roomPath = regexp.MustCompile(RoomPathPattern)
+
+// RoomID returns the numeric room ID of a /rooms/<id> URL, or 0.
+func RoomID(u string) int64 {
+	m := roomPath.FindStringSubmatch(u)
+	if m == nil {
+		return 0
+	}
+	id, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
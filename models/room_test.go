@@ -0,0 +1,24 @@
+package models
+
+import "testing"
+
+func TestRoomID(t *testing.T) {
+	tests := []struct {
+		url  string
+		want int64
+	}{
+		{"https://www.airbnb.com/rooms/41001101", 41001101},
+		{"https://www.airbnb.com/rooms/41001101?source_impression_id=p3_1&check_in=2026-11-02", 41001101},
+		{"https://www.airbnb.co.uk/rooms/plus/52345", 52345},
+		{"/rooms/1234567890123456789", 1234567890123456789},
+		{"https://www.airbnb.com/rooms/99999999999999999999", 0}, // overflows int64
+		{"https://www.airbnb.com/s/Bali/homes", 0},
+		{"https://www.airbnb.com/rooms/", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := RoomID(tt.url); got != tt.want {
+			t.Errorf("RoomID(%q) = %d, want %d", tt.url, got, tt.want)
+		}
+	}
+}
@@ -55,7 +55,7 @@ main() {
   wait_for_postgres "airbnb-scraper-postgres"
 
   info "Running Go scraper..."
-  go run main.go
+  go run main.go "$@"
 
   info "Run complete."
 }
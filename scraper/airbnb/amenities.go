@@ -0,0 +1,110 @@
+package airbnb
+
+import (
+	"airbnb-scraper/models"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// notIncluded is the heading Airbnb groups unavailable amenities under.
+const notIncluded = "not included"
+
+var unavailablePrefix = regexp.MustCompile(`(?i)^unavailable:\s*`)
+
+// parseAmenities reads an amenities list in any of the shapes the
+// default selector set produces:
+//
+//   - Airbnb's amenity groups, [{"title": "Bathroom", "amenities":
+//     [{"title": "Hair dryer", "available": true}]}], from the API or
+//     page state
+//   - JSON-LD amenityFeature, [{"name": "Wifi", "value": true}]
+//   - the modal script's [{"name", "category", "available"}]
+//   - plain text separated by ";" or newlines
+//
+// Names are deduplicated case-insensitively, keeping the first entry.
+func parseAmenities(raw string) []models.Amenity {
+	var v interface{}
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		var out []models.Amenity
+		for _, name := range strings.FieldsFunc(raw, func(r rune) bool { return r == ';' || r == '\n' }) {
+			out = append(out, amenity(name, "", true))
+		}
+		return dedupeAmenities(out)
+	}
+
+	var out []models.Amenity
+	collectAmenities(v, "", true, &out)
+	return dedupeAmenities(out)
+}
+
+func collectAmenities(v interface{}, category string, available bool, out *[]models.Amenity) {
+	switch t := v.(type) {
+	case []interface{}:
+		for _, item := range t {
+			collectAmenities(item, category, available, out)
+		}
+
+	case map[string]interface{}:
+		title := firstString(t, "title", "name")
+
+		// A group: its title is the category of everything in it.
+		if items, ok := t["amenities"]; ok {
+			if strings.EqualFold(title, notIncluded) {
+				collectAmenities(items, category, false, out)
+			} else {
+				collectAmenities(items, title, available, out)
+			}
+			return
+		}
+
+		if c := firstString(t, "category"); c != "" {
+			category = c
+		}
+		avail := available
+		for _, key := range []string{"available", "value"} {
+			if b, ok := t[key].(bool); ok {
+				avail = avail && b
+				break
+			}
+		}
+		*out = append(*out, amenity(title, category, avail))
+
+	case string:
+		*out = append(*out, amenity(t, category, available))
+	}
+}
+
+// amenity cleans a scraped name; "Unavailable: TV" becomes an
+// unavailable "TV".
+func amenity(name, category string, available bool) models.Amenity {
+	name = strings.Join(strings.Fields(name), " ")
+	if unavailablePrefix.MatchString(name) {
+		name = unavailablePrefix.ReplaceAllString(name, "")
+		available = false
+	}
+	return models.Amenity{Name: name, Category: strings.TrimSpace(category), Available: available}
+}
+
+func dedupeAmenities(in []models.Amenity) []models.Amenity {
+	seen := make(map[string]bool)
+	var out []models.Amenity
+	for _, a := range in {
+		key := strings.ToLower(a.Name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, a)
+	}
+	return out
+}
+
+func firstString(obj map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		if s, ok := obj[k].(string); ok && strings.TrimSpace(s) != "" {
+			return strings.TrimSpace(s)
+		}
+	}
+	return ""
+}
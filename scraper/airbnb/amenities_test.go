@@ -0,0 +1,146 @@
+package airbnb
+
+import (
+	"airbnb-scraper/models"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestAmenitiesFromSection reads the amenity groups the way the api and
+// state strategies do: the AmenitiesSection of a StaysPdpSections body.
+func TestAmenitiesFromSection(t *testing.T) {
+	const body = `{"data": {"presentation": {"stayProductDetailPage": {"sections": {"sections": [
+		{"section": {"__typename": "PdpTitleSection", "title": "Skyline studio"}},
+		{"section": {"__typename": "AmenitiesSection", "seeAllAmenitiesGroups": [
+			{"title": "Bathroom", "amenities": [
+				{"title": "Hair dryer", "available": true},
+				{"title": "Shampoo, conditioner", "available": true}
+			]},
+			{"title": "Internet and office", "amenities": [
+				{"title": "Wifi", "available": true},
+				{"title": "Dedicated workspace", "available": false}
+			]},
+			{"title": "Not included", "amenities": [
+				{"title": "Unavailable: TV", "available": true},
+				{"title": "Smoke alarm"}
+			]}
+		]}}
+	]}}}}}`
+
+	var root interface{}
+	if err := json.Unmarshal([]byte(body), &root); err != nil {
+		t.Fatal(err)
+	}
+	want := []models.Amenity{
+		{Name: "Hair dryer", Category: "Bathroom", Available: true},
+		{Name: "Shampoo, conditioner", Category: "Bathroom", Available: true},
+		{Name: "Wifi", Category: "Internet and office", Available: true},
+		{Name: "Dedicated workspace", Category: "Internet and office"},
+		{Name: "TV"},
+		{Name: "Smoke alarm"},
+	}
+
+	for _, source := range []string{sourceAPI, sourceState} {
+		ex := &pathExtractor{name: source + "-amenity-groups", source: source, paths: []string{"**.section[__typename=AmenitiesSection].seeAllAmenitiesGroups"}}
+		data := &structuredData{api: []interface{}{root}, state: []interface{}{root}}
+		raw, err := ex.Extract(&Page{data: data})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := parseAmenities(raw); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s amenities:\n got %+v\nwant %+v", source, got, want)
+		}
+	}
+}
+
+func TestParseAmenities(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []models.Amenity
+	}{
+		{
+			name: "JSON-LD amenityFeature",
+			raw:  `[{"@type": "LocationFeatureSpecification", "name": "Wifi", "value": true}, {"name": "Pool", "value": false}]`,
+			want: []models.Amenity{{Name: "Wifi", Available: true}, {Name: "Pool"}},
+		},
+		{
+			name: "modal script",
+			raw: `[{"name": "Kitchen", "category": "Kitchen and dining", "available": true},
+				{"name": "Washer", "category": "Bedroom and laundry", "available": false},
+				{"name": "Carbon monoxide alarm", "category": "", "available": false}]`,
+			want: []models.Amenity{
+				{Name: "Kitchen", Category: "Kitchen and dining", Available: true},
+				{Name: "Washer", Category: "Bedroom and laundry"},
+				{Name: "Carbon monoxide alarm"},
+			},
+		},
+		{
+			name: "modal row read with its prefix",
+			raw:  `[{"name": "Unavailable: Essentials", "category": "Bathroom", "available": true}]`,
+			want: []models.Amenity{{Name: "Essentials", Category: "Bathroom"}},
+		},
+		{
+			name: "plain text",
+			raw:  "Wifi; Kitchen\nFree parking on premises;  Air   conditioning ",
+			want: []models.Amenity{
+				{Name: "Wifi", Available: true},
+				{Name: "Kitchen", Available: true},
+				{Name: "Free parking on premises", Available: true},
+				{Name: "Air conditioning", Available: true},
+			},
+		},
+		{
+			name: "plain text with unavailable items",
+			raw:  "Wifi\nUnavailable: TV\nunavailable:Heating",
+			want: []models.Amenity{{Name: "Wifi", Available: true}, {Name: "TV"}, {Name: "Heating"}},
+		},
+		{
+			name: "duplicates keep the first entry",
+			raw: `[{"title": "Essentials", "amenities": [{"title": "Wifi", "available": true}]},
+				{"title": "Internet and office", "amenities": [{"title": "wifi", "available": false}, {"title": "WIFI"}]}]`,
+			want: []models.Amenity{{Name: "Wifi", Category: "Essentials", Available: true}},
+		},
+		{
+			name: "unavailable first wins over a later available duplicate",
+			raw:  "Unavailable: TV\nTV",
+			want: []models.Amenity{{Name: "TV"}},
+		},
+		{
+			name: "empty names dropped",
+			raw:  `[{"title": ""}, {"name": "  "}, "Pool"]`,
+			want: []models.Amenity{{Name: "Pool", Available: true}},
+		},
+		{name: "empty", raw: "", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseAmenities(tt.raw); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAmenities(%q):\n got %+v\nwant %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestUnavailableAmenitiesExcluded checks that a listing does not offer
+// what its page lists as "Unavailable:".
+func TestUnavailableAmenitiesExcluded(t *testing.T) {
+	l := models.Listing{Amenities: parseAmenities("Wifi\nUnavailable: TV\nUnavailable: Smoke alarm")}
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Wifi", true},
+		{"wifi", true},
+		{"TV", false},
+		{"Smoke alarm", false},
+		{"Unavailable: TV", false},
+		{"Pool", false},
+	}
+	for _, tt := range tests {
+		if got := l.HasAmenity(tt.name); got != tt.want {
+			t.Errorf("HasAmenity(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
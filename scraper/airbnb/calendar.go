@@ -0,0 +1,173 @@
+package airbnb
+
+import (
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// calendarDaysJS reads the days of the calendar widget's visible months
+// in the PdpAvailabilityCalendar shape, so parseCalendar handles both.
+const calendarDaysJS = `(() => {
+	const days = Array.from(document.querySelectorAll('[data-testid^="calendar-day-"]'))
+		.filter(el => el.getClientRects().length > 0)
+		.map(el => {
+			const m = el.getAttribute('data-testid').match(/calendar-day-([0-9]{2})\/([0-9]{2})\/([0-9]{4})/);
+			if (!m) return null;
+			const label = el.getAttribute('aria-label') || '';
+			const blocked = el.getAttribute('data-is-day-blocked');
+			const min = label.match(/([0-9]+)[- ]nights? minimum/i) || label.match(/minimum stay (?:is )?([0-9]+) nights?/i);
+			const price = (el.innerText || '').match(/(?:[$€£]|RM\s?)[0-9][0-9.,]*/);
+			return {
+				calendarDate: m[3] + '-' + m[1] + '-' + m[2],
+				available: blocked !== null ? blocked !== 'true' : !/not available|unavailable/i.test(label),
+				minNights: min ? Number(min[1]) : 0,
+				price: price ? price[0] : '',
+			};
+		})
+		.filter(Boolean);
+	return days.length ? JSON.stringify(days) : '';
+})()`
+
+// scrapeCalendar reads the listing's availability calendar from today
+// to cfg.CalendarMonths months ahead: from the captured
+// PdpAvailabilityCalendar response when there is one, otherwise by paging
+// the calendar widget forward a month at a time. It gets its own
+// RequestTimeout.
+func (s *Scraper) scrapeCalendar(tabCtx context.Context, capture *apiCapture) ([]models.CalendarDay, error) {
+	ctx, cancel := context.WithTimeout(tabCtx, s.cfg.RequestTimeout)
+	defer cancel()
+
+	// The calendar loads when it scrolls into view.
+	var widget bool
+	err := chromedp.Run(ctx, chromedp.Evaluate(`(() => {
+		const section = document.querySelector('[data-section-id^="AVAILABILITY_CALENDAR"]');
+		if (section) section.scrollIntoView();
+		return !!document.querySelector('[data-testid^="calendar-day-"]');
+	})()`, &widget))
+	if err != nil {
+		return nil, err
+	}
+	if !widget {
+		capture.wait(ctx, opPdpAvailabilityCalendar, 2*time.Second)
+	}
+
+	today := time.Now()
+	end := calendarEnd(today, s.cfg.CalendarMonths)
+
+	var days []models.CalendarDay
+	for _, payload := range capture.payloads(opPdpAvailabilityCalendar) {
+		collectCalendar(payload, &days)
+	}
+
+	// Each page shows at least one new month; the extra pages cover a
+	// widget that starts before the current month.
+	paging := len(days) == 0 && widget
+	for page := 0; paging && page <= s.cfg.CalendarMonths+1; page++ {
+		var raw string
+		if err := chromedp.Run(ctx, chromedp.Evaluate(calendarDaysJS, &raw)); err != nil {
+			return nil, err
+		}
+		seen := parseCalendar(raw)
+		if len(seen) == 0 {
+			break
+		}
+		days = append(days, seen...)
+		if seen[len(seen)-1].Date >= end {
+			break
+		}
+
+		var moved bool
+		err := chromedp.Run(ctx,
+			chromedp.Evaluate(`(() => {
+				const next = document.querySelector('button[aria-label*="move forward" i], button[aria-label*="next month" i]');
+				if (!next || next.disabled) return false;
+				next.click();
+				return true;
+			})()`, &moved),
+			chromedp.Sleep(300*time.Millisecond),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if !moved {
+			break
+		}
+	}
+
+	return calendarWindow(days, today, end), nil
+}
+
+// calendarEnd is the first day after a calendar of months months that
+// starts on today, as YYYY-MM-DD.
+func calendarEnd(today time.Time, months int) string {
+	day := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, months, 0).Format(config.DateLayout)
+}
+
+// calendarWindow keeps the days from today up to end, once each and in
+// date order. Calendars start at the first of the month, and the days
+// already past show as blocked.
+func calendarWindow(days []models.CalendarDay, today time.Time, end string) []models.CalendarDay {
+	start := today.Format(config.DateLayout)
+	byDate := make(map[string]models.CalendarDay)
+	for _, d := range days {
+		if d.Date >= start && d.Date < end {
+			byDate[d.Date] = d
+		}
+	}
+
+	out := make([]models.CalendarDay, 0, len(byDate))
+	for _, d := range byDate {
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Date < out[j].Date })
+	return out
+}
+
+// parseCalendar reads calendar days from a PdpAvailabilityCalendar
+// response (calendarMonths[].days[] with "calendarDate", "available",
+// "minNights" and "price": {"localPriceFormatted"}) or calendarDaysJS.
+func parseCalendar(raw string) []models.CalendarDay {
+	var v interface{}
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		return nil
+	}
+	var out []models.CalendarDay
+	collectCalendar(v, &out)
+	return out
+}
+
+func collectCalendar(v interface{}, out *[]models.CalendarDay) {
+	switch t := v.(type) {
+	case []interface{}:
+		for _, item := range t {
+			collectCalendar(item, out)
+		}
+
+	case map[string]interface{}:
+		date := firstString(t, "calendarDate")
+		if _, err := time.Parse(config.DateLayout, date); err != nil {
+			for _, child := range t {
+				collectCalendar(child, out)
+			}
+			return
+		}
+
+		d := models.CalendarDay{Date: date}
+		d.Available, _ = strconv.ParseBool(scalarString(t["available"]))
+		d.MinNights, _ = strconv.Atoi(scalarString(t["minNights"]))
+		price := scalarString(t["price"])
+		if p, ok := t["price"].(map[string]interface{}); ok {
+			price = firstString(p, "localPriceFormatted", "localPrice")
+		}
+		d.Price = parsePrice(price)
+		*out = append(*out, d)
+	}
+}
@@ -0,0 +1,81 @@
+package airbnb
+
+import (
+	"airbnb-scraper/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseCalendar(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []models.CalendarDay
+	}{
+		{
+			name: "API response",
+			raw: `{"data": {"merlin": {"pdpAvailabilityCalendar": {"calendarMonths": [
+				{"month": 11, "year": 2026, "days": [
+					{"calendarDate": "2026-11-01", "available": false, "minNights": 2, "price": {"localPriceFormatted": null}},
+					{"calendarDate": "2026-11-02", "available": true, "minNights": 2, "price": {"localPriceFormatted": "$1,120"}}
+				]},
+				{"month": 12, "year": 2026, "days": [
+					{"calendarDate": "2026-12-01", "available": true, "minNights": 3, "price": {"localPrice": "RM 95"}}
+				]}
+			]}}}}`,
+			want: []models.CalendarDay{
+				{Date: "2026-11-01", Available: false, MinNights: 2},
+				{Date: "2026-11-02", Available: true, MinNights: 2, Price: 1120},
+				{Date: "2026-12-01", Available: true, MinNights: 3, Price: 95},
+			},
+		},
+		{
+			name: "page script result with plain values",
+			raw:  `[{"calendarDate": "2026-11-03", "available": "true", "minNights": "1", "price": "$80"}, {"calendarDate": "2026-11-04", "available": "false"}]`,
+			want: []models.CalendarDay{
+				{Date: "2026-11-03", Available: true, MinNights: 1, Price: 80},
+				{Date: "2026-11-04"},
+			},
+		},
+		{
+			name: "entries without a valid date are skipped",
+			raw:  `[{"calendarDate": "Nov 5", "available": true}, {"available": true}, {"calendarDate": "2026-11-05", "available": true}]`,
+			want: []models.CalendarDay{{Date: "2026-11-05", Available: true}},
+		},
+		{name: "not JSON", raw: "Loading calendar…", want: nil},
+		{name: "empty", raw: "", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseCalendar(tt.raw); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCalendar:\n got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendarWindow(t *testing.T) {
+	today := time.Date(2026, 11, 3, 15, 4, 0, 0, time.UTC)
+	end := calendarEnd(today, 1)
+	if end != "2026-12-03" {
+		t.Fatalf("calendarEnd = %s, want 2026-12-03", end)
+	}
+
+	days := []models.CalendarDay{
+		{Date: "2026-12-03", Available: true}, // end is exclusive
+		{Date: "2026-11-04", Available: true},
+		{Date: "2026-11-02"}, // already past
+		{Date: "2026-11-03", Available: true},
+		{Date: "2026-11-04", Available: true},
+		{Date: "2026-12-02"},
+	}
+	want := []models.CalendarDay{
+		{Date: "2026-11-03", Available: true},
+		{Date: "2026-11-04", Available: true},
+		{Date: "2026-12-02"},
+	}
+	if got := calendarWindow(days, today, end); !reflect.DeepEqual(got, want) {
+		t.Errorf("calendarWindow:\n got %+v\nwant %+v", got, want)
+	}
+}
@@ -0,0 +1,88 @@
+package airbnb
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Room types, as Airbnb names them in its own listing data.
+const (
+	RoomEntireHome = "Entire home/apt"
+	RoomPrivate    = "Private room"
+	RoomShared     = "Shared room"
+	RoomHotel      = "Hotel room"
+)
+
+// listingKind splits an overview heading such as "Entire rental unit in
+// Kuala Lumpur, Malaysia" or "Private room in home" into the room type
+// and the property type ("rental unit", "home").
+func listingKind(raw string) (roomType, propertyType string) {
+	s := strings.TrimSpace(raw)
+	lower := strings.ToLower(s)
+	prefixes := []struct{ prefix, roomType string }{
+		{"entire ", RoomEntireHome},
+		{"private room in ", RoomPrivate},
+		{"private room", RoomPrivate},
+		{"shared room in ", RoomShared},
+		{"shared room", RoomShared},
+		{"room in ", RoomHotel},
+		{"hotel room in ", RoomHotel},
+		{"hotel room", RoomHotel},
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(lower, p.prefix) {
+			// Whatever follows the next " in " is the location:
+			// "Private room in home in Lisbon" → "home".
+			rest := s[len(p.prefix):]
+			if i := strings.Index(strings.ToLower(rest), " in "); i >= 0 {
+				rest = rest[:i]
+			}
+			return p.roomType, strings.ToLower(strings.TrimSpace(rest))
+		}
+	}
+
+	// No recognizable room type: the whole value is a property type,
+	// e.g. "Condo" from structured data.
+	if i := strings.Index(lower, " in "); i >= 0 {
+		s = s[:i]
+	}
+	return "", strings.ToLower(strings.TrimSpace(s))
+}
+
+var (
+	bathPattern   = regexp.MustCompile(`(?i)([0-9]+(?:\.[0-9]+)?)\s+(?:(?:shared|private|dedicated)\s+)?(?:half-)?bath`)
+	halfPattern   = regexp.MustCompile(`(?i)\bhalf[- ]?bath`)
+	sharedPattern = regexp.MustCompile(`(?i)\bshared\b`)
+)
+
+// parseBathrooms reads the bathroom part of an overview line ("1.5
+// shared baths", "1 private bath", "Half-bath") or a bare number from
+// structured data, and reports whether the bathroom is shared.
+func parseBathrooms(raw string) (float64, bool) {
+	part := raw
+	for _, p := range strings.Split(raw, "·") {
+		if strings.Contains(strings.ToLower(p), "bath") {
+			part = p
+			break
+		}
+	}
+	shared := sharedPattern.MatchString(part)
+	half := halfPattern.MatchString(part)
+
+	if m := bathPattern.FindStringSubmatch(part); m != nil {
+		n, _ := strconv.ParseFloat(m[1], 64)
+		if half {
+			n /= 2 // "2 half-baths"
+		}
+		return n, shared
+	}
+	if half {
+		return 0.5, shared
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+	if err != nil {
+		return 0, shared
+	}
+	return n, shared
+}
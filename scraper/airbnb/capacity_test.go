@@ -0,0 +1,58 @@
+package airbnb
+
+import "testing"
+
+func TestParseBathrooms(t *testing.T) {
+	tests := []struct {
+		raw        string
+		want       float64
+		wantShared bool
+	}{
+		{"1 bath", 1, false},
+		{"2 baths", 2, false},
+		{"1.5 baths", 1.5, false},
+		{"1.5 shared baths", 1.5, true},
+		{"1 private bath", 1, false},
+		{"1 dedicated bath", 1, false},
+		{"Shared half-bath", 0.5, true},
+		{"Half-bath", 0.5, false},
+		{"2 half-baths", 1, false},
+		{"4 guests · 2 bedrooms · 3 beds · 2.5 baths", 2.5, false},
+		{"2 guests · 1 bedroom · 1 bed · 1 shared bath", 1, true},
+		{"3", 3, false},
+		{" 1.5 ", 1.5, false},
+		{"", 0, false},
+		{"Studio", 0, false},
+	}
+	for _, tt := range tests {
+		got, shared := parseBathrooms(tt.raw)
+		if got != tt.want || shared != tt.wantShared {
+			t.Errorf("parseBathrooms(%q) = %v, %v; want %v, %v", tt.raw, got, shared, tt.want, tt.wantShared)
+		}
+	}
+}
+
+func TestListingKind(t *testing.T) {
+	tests := []struct {
+		raw          string
+		roomType     string
+		propertyType string
+	}{
+		{"Entire rental unit in Kuala Lumpur, Malaysia", RoomEntireHome, "rental unit"},
+		{"Entire home", RoomEntireHome, "home"},
+		{"Private room in home in Lisbon, Portugal", RoomPrivate, "home"},
+		{"Private room", RoomPrivate, ""},
+		{"Shared room in hostel", RoomShared, "hostel"},
+		{"Room in boutique hotel in Bangkok", RoomHotel, "boutique hotel"},
+		{"Hotel room in boutique hotel in Paris, France", RoomHotel, "boutique hotel"},
+		{"Condo", "", "condo"},
+		{"Villa in Ubud, Indonesia", "", "villa"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		roomType, propertyType := listingKind(tt.raw)
+		if roomType != tt.roomType || propertyType != tt.propertyType {
+			t.Errorf("listingKind(%q) = %q, %q; want %q, %q", tt.raw, roomType, propertyType, tt.roomType, tt.propertyType)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package airbnb
+
+import (
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// searchCard is what one search results card shows, as read from the
+// page. Price is the card's price line ("$120 night", "$540 total",
+// "$150 $120 night" when discounted); Rating is "4.85 (120)" or "New".
+type searchCard struct {
+	URL       string `json:"url"`
+	Title     string `json:"title"`
+	Price     string `json:"price"`
+	Rating    string `json:"rating"`
+	Thumbnail string `json:"thumbnail"`
+}
+
+// GetListingsFromSection builds listings straight from a section's
+// search results cards, without opening any detail page: ID, title,
+// price, rating, review count and thumbnail, plus the coordinates and
+// neighborhood when the search response has them. It pages through the
+// section like GetPropertyURLsFromSection.
+func (s *Scraper) GetListingsFromSection(ctx context.Context, sectionURL string) ([]models.Listing, error) {
+	cards, err := s.collectCards(ctx, sectionURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var listings []models.Listing
+	for _, c := range cards {
+		if !s.markSeenIfNew(c.URL) {
+			continue
+		}
+		if l := s.listingFromCard(c); l.Title != "" {
+			listings = append(listings, l)
+		}
+	}
+	utils.Success("Got %d listings from section cards", len(listings))
+	return listings, nil
+}
+
+// listingFromCard turns a card into a listing. The StaysSearch result for
+// the room, when one was captured, wins over the card's text: it has the
+// exact price and rating and the listing's name rather than the card
+// heading. In search mode with dates, prices are for the searched stay.
+func (s *Scraper) listingFromCard(c searchCard) models.Listing {
+	l := models.Listing{
+		ID:       models.RoomID(c.URL),
+		Platform: "airbnb",
+		Title:    c.Title,
+		URL:      c.URL,
+	}
+	if s.cfg.SearchMode() && s.cfg.CheckIn != "" {
+		l.CheckIn, l.CheckOut = s.cfg.CheckIn, s.cfg.CheckOut
+	}
+
+	thumbnail := c.Thumbnail
+	if r, ok := s.searchResultFor(c.URL); ok {
+		if r.Title != "" {
+			l.Title = r.Title
+		}
+		if r.RawPrice != "" {
+			l.RawPrice = r.RawPrice
+			l.Price = parsePrice(r.RawPrice)
+			setPriceUnit(&l, r.PriceQualifier)
+		}
+		l.Rating, l.ReviewCount = r.Rating, r.ReviewCount
+		l.Latitude, l.Longitude = r.Latitude, r.Longitude
+		l.Neighborhood = r.Neighborhood
+		if r.Thumbnail != "" {
+			thumbnail = r.Thumbnail
+		}
+	}
+
+	if l.Price == 0 {
+		setCardPrice(&l, c.Price)
+	}
+	if m := ratingPattern.FindStringSubmatch(c.Rating); m != nil && l.Rating == 0 {
+		l.Rating = parseRating(m[1])
+		l.ReviewCount, _ = strconv.Atoi(strings.ReplaceAll(m[2], ",", ""))
+	}
+	l.Thumbnail = s.thumbnailURL(thumbnail)
+	fillPricing(&l)
+	return l
+}
+
+// setCardPrice reads a card's price line. A discounted card shows the
+// old price first, so the last amount is the price; the rest of the line
+// says what it is for.
+func setCardPrice(l *models.Listing, raw string) {
+	all := moneyPattern.FindAllStringSubmatchIndex(raw, -1)
+	if len(all) == 0 {
+		return
+	}
+	last := all[len(all)-1]
+	l.RawPrice = strings.TrimSpace(raw[last[0]:last[1]])
+	l.Price = parsePrice(raw[last[4]:last[5]])
+	setPriceUnit(l, raw[last[0]:])
+}
+
+// thumbnailURL is a card picture without Airbnb's resizing parameters,
+// made absolute against the crawled origin.
+func (s *Scraper) thumbnailURL(raw string) string {
+	u := photoURL(raw)
+	if u == "" {
+		return ""
+	}
+	base, err := url.Parse(s.origin())
+	if err != nil {
+		return u
+	}
+	ref, err := url.Parse(u)
+	if err != nil {
+		return u
+	}
+	return base.ResolveReference(ref).String()
+}
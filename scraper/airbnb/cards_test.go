@@ -0,0 +1,106 @@
+package airbnb
+
+import (
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"reflect"
+	"testing"
+)
+
+func TestSetCardPrice(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want models.Listing
+	}{
+		{"$120 night", models.Listing{RawPrice: "$120", Price: 120, PricePerNight: 120}},
+		{"$150 $120 night", models.Listing{RawPrice: "$120", Price: 120, PricePerNight: 120}},
+		{"$540 total", models.Listing{RawPrice: "$540", Price: 540, TotalPrice: 540}},
+		{"$600 for 5 nights", models.Listing{RawPrice: "$600", Price: 600, TotalPrice: 600, Nights: 5}},
+		{"RM 1,250 for 5 nights", models.Listing{RawPrice: "RM 1,250", Price: 1250, TotalPrice: 1250, Nights: 5}},
+		{"$95", models.Listing{RawPrice: "$95", Price: 95}},
+		{"Price unavailable", models.Listing{}},
+		{"", models.Listing{}},
+	}
+	for _, tt := range tests {
+		var got models.Listing
+		setCardPrice(&got, tt.raw)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("setCardPrice(%q):\n got %+v\nwant %+v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestListingFromCard(t *testing.T) {
+	const room = "https://www.airbnb.com/rooms/41001101"
+
+	tests := []struct {
+		name   string
+		card   searchCard
+		result *searchResult
+		search bool
+		want   models.Listing
+	}{
+		{
+			name: "card text only",
+			card: searchCard{URL: room, Title: "Condo in Bukit Bintang", Price: "$150 $120 night", Rating: "4.85 (1,120)", Thumbnail: "/im/pictures/1.jpg?im_w=720"},
+			want: models.Listing{
+				ID: 41001101, Platform: "airbnb", Title: "Condo in Bukit Bintang", URL: room,
+				RawPrice: "$120", Price: 120, PricePerNight: 120,
+				Rating: 4.85, ReviewCount: 1120,
+				Thumbnail: "https://www.airbnb.com/im/pictures/1.jpg",
+			},
+		},
+		{
+			name: "new listing has no rating",
+			card: searchCard{URL: room, Title: "Loft", Price: "$600 for 5 nights", Rating: "New"},
+			want: models.Listing{
+				ID: 41001101, Platform: "airbnb", Title: "Loft", URL: room,
+				RawPrice: "$600", Price: 600, TotalPrice: 600, Nights: 5, PricePerNight: 120,
+			},
+		},
+		{
+			name: "search result wins over the card",
+			card: searchCard{URL: room + "?adults=2", Title: "Condo in Bukit Bintang", Price: "$99 night", Rating: "4.1 (3)", Thumbnail: "/im/pictures/card.jpg"},
+			result: &searchResult{
+				RoomID: "41001101", Title: "Skyline studio", RawPrice: "$540", PriceQualifier: "total",
+				Rating: 4.97, ReviewCount: 88, Latitude: 3.146, Longitude: 101.711,
+				Neighborhood: "Bukit Bintang", Thumbnail: "https://a0.muscache.com/im/pictures/api.jpg?im_w=720",
+			},
+			search: true,
+			want: models.Listing{
+				ID: 41001101, Platform: "airbnb", Title: "Skyline studio", URL: room + "?adults=2",
+				RawPrice: "$540", Price: 540, TotalPrice: 540, Nights: 3, PricePerNight: 180,
+				Rating: 4.97, ReviewCount: 88, Latitude: 3.146, Longitude: 101.711,
+				Neighborhood: "Bukit Bintang", CheckIn: "2026-11-02", CheckOut: "2026-11-05",
+				Thumbnail: "https://a0.muscache.com/im/pictures/api.jpg",
+			},
+		},
+		{
+			name:   "search result without a price keeps the card's",
+			card:   searchCard{URL: room, Title: "Loft", Price: "$80 night", Rating: "New"},
+			result: &searchResult{RoomID: "41001101", Rating: 4.5, ReviewCount: 2},
+			want: models.Listing{
+				ID: 41001101, Platform: "airbnb", Title: "Loft", URL: room,
+				RawPrice: "$80", Price: 80, PricePerNight: 80, Rating: 4.5, ReviewCount: 2,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.BaseURL = "https://www.airbnb.com/"
+			if tt.search {
+				cfg.SearchLocation = "Kuala Lumpur"
+				cfg.CheckIn, cfg.CheckOut = "2026-11-02", "2026-11-05"
+			}
+			s := &Scraper{cfg: cfg, searchResults: make(map[string]searchResult)}
+			if tt.result != nil {
+				s.searchResults[tt.result.RoomID] = *tt.result
+			}
+
+			if got := s.listingFromCard(tt.card); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("listingFromCard:\n got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
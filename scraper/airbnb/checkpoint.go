@@ -0,0 +1,416 @@
+package airbnb
+
+import (
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Property states in a Checkpoint.
+const (
+	PropertyPending = "pending"
+	PropertyDone    = "done"
+	PropertyFailed  = "failed"
+)
+
+// Checkpoint is the crawl frontier of one run, kept in two files in dir
+// so an interrupted run can be resumed: sections already collected are
+// not reopened and completed properties are not scraped again.
+//
+//	<run-id>.json   the sections and the property URLs each one yielded,
+//	                rewritten when a section is collected
+//	<run-id>.jsonl  one line per property outcome (done with its listing,
+//	                or failed), appended as pages finish
+//
+// Each listing is written once, so a run's checkpoint I/O grows with the
+// number of properties rather than with its square.
+type Checkpoint struct {
+	RunID     string    `json:"run_id"`
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Sections are the section URLs discovered for the run, in order.
+	Sections []*SectionState `json:"sections"`
+
+	// Properties tracks every queued property URL. It is rebuilt from the
+	// log on load; checkpoints of earlier versions kept it in the .json.
+	Properties map[string]*PropertyState `json:"properties,omitempty"`
+
+	path    string
+	logPath string
+	mu      sync.Mutex
+	logMu   sync.Mutex
+}
+
+// SectionState is one section and the property URLs it yielded.
+type SectionState struct {
+	URL        string   `json:"url"`
+	Collected  bool     `json:"collected"`
+	Properties []string `json:"properties,omitempty"`
+}
+
+// PropertyState is the outcome of one property page. Listing is kept for
+// completed pages so a resumed run still returns them.
+type PropertyState struct {
+	Status  string          `json:"status"`
+	Error   string          `json:"error,omitempty"`
+	Listing *models.Listing `json:"listing,omitempty"`
+}
+
+// propertyEntry is one line of the property log.
+type propertyEntry struct {
+	URL string `json:"url"`
+	PropertyState
+}
+
+// NewRunID returns an ID for a new run: the start time and a random
+// suffix, e.g. "20261016-193012-4f2a", so runs started in the same
+// second still get their own checkpoint.
+func NewRunID() string {
+	var suffix [2]byte
+	rand.Read(suffix[:])
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(suffix[:])
+}
+
+// NewCheckpoint starts an empty checkpoint for runID in dir. It fails if
+// the run already has one.
+func NewCheckpoint(dir, runID string) (*Checkpoint, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create checkpoint dir: %w", err)
+	}
+	path := checkpointPath(dir, runID)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if errors.Is(err, fs.ErrExist) {
+		return nil, fmt.Errorf("checkpoint %s already exists", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not create checkpoint: %w", err)
+	}
+	f.Close()
+
+	now := time.Now()
+	c := &Checkpoint{
+		RunID:      runID,
+		StartedAt:  now,
+		UpdatedAt:  now,
+		Properties: make(map[string]*PropertyState),
+		path:       path,
+		logPath:    propertyLogPath(dir, runID),
+	}
+	return c, c.save()
+}
+
+// LoadCheckpoint reads the checkpoint of an earlier run.
+func LoadCheckpoint(dir, runID string) (*Checkpoint, error) {
+	path := checkpointPath(dir, runID)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read checkpoint for run %q: %w", runID, err)
+	}
+
+	var c Checkpoint
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+	if c.Properties == nil {
+		c.Properties = make(map[string]*PropertyState)
+	}
+	c.path = path
+	c.logPath = propertyLogPath(dir, runID)
+	if err := c.readLog(); err != nil {
+		return nil, err
+	}
+	for _, s := range c.Sections {
+		for _, u := range s.Properties {
+			if _, ok := c.Properties[u]; !ok {
+				c.Properties[u] = &PropertyState{Status: PropertyPending}
+			}
+		}
+	}
+	return &c, nil
+}
+
+// readLog applies the property log, later lines winning. A last line cut
+// short by a crash mid-write is dropped from the file, so the resumed run
+// appends after the last complete entry.
+func (c *Checkpoint) readLog() error {
+	b, err := os.ReadFile(c.logPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not read checkpoint log: %w", err)
+	}
+
+	good := 0
+	for good < len(b) {
+		line, _, complete := bytes.Cut(b[good:], []byte("\n"))
+		if !complete {
+			break
+		}
+		good += len(line) + 1
+		var e propertyEntry
+		if err := json.Unmarshal(line, &e); err != nil {
+			utils.Warn("Checkpoint log %s: skipping invalid entry: %v", c.logPath, err)
+			continue
+		}
+		st := e.PropertyState
+		c.Properties[e.URL] = &st
+	}
+
+	if good < len(b) {
+		utils.Warn("Checkpoint log %s ends with a partial entry; dropping it", c.logPath)
+		if err := os.Truncate(c.logPath, int64(good)); err != nil {
+			return fmt.Errorf("could not repair checkpoint log: %w", err)
+		}
+	}
+	return nil
+}
+
+func checkpointPath(dir, runID string) string {
+	return filepath.Join(dir, runID+".json")
+}
+
+func propertyLogPath(dir, runID string) string {
+	return filepath.Join(dir, runID+".jsonl")
+}
+
+// sectionURLs returns the section URLs recorded for the run, if any.
+func (c *Checkpoint) sectionURLs() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	urls := make([]string, len(c.Sections))
+	for i, s := range c.Sections {
+		urls[i] = s.URL
+	}
+	return urls
+}
+
+func (c *Checkpoint) setSections(urls []string) error {
+	c.mu.Lock()
+	c.Sections = make([]*SectionState, len(urls))
+	for i, u := range urls {
+		c.Sections[i] = &SectionState{URL: u}
+	}
+	c.mu.Unlock()
+	return c.save()
+}
+
+// sectionProperties returns the property URLs already collected for
+// section i, and whether the section was collected at all.
+func (c *Checkpoint) sectionProperties(i int) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if i >= len(c.Sections) || !c.Sections[i].Collected {
+		return nil, false
+	}
+	return c.Sections[i].Properties, true
+}
+
+// setSectionProperties records what section i yielded and queues every
+// new property URL as pending.
+func (c *Checkpoint) setSectionProperties(i int, urls []string) error {
+	c.mu.Lock()
+	if i < len(c.Sections) {
+		c.Sections[i].Collected = true
+		c.Sections[i].Properties = urls
+	}
+	for _, u := range urls {
+		if _, ok := c.Properties[u]; !ok {
+			c.Properties[u] = &PropertyState{Status: PropertyPending}
+		}
+	}
+	c.mu.Unlock()
+	return c.save()
+}
+
+// setSectionListings records a section whose listings came straight from
+// its cards: the section is collected and every listing is done at once.
+func (c *Checkpoint) setSectionListings(i int, listings []models.Listing) error {
+	urls := make([]string, len(listings))
+	entries := make([]propertyEntry, len(listings))
+	c.mu.Lock()
+	for j, l := range listings {
+		urls[j] = l.URL
+		entries[j] = propertyEntry{URL: l.URL, PropertyState: PropertyState{Status: PropertyDone, Listing: &l}}
+		c.Properties[l.URL] = &entries[j].PropertyState
+	}
+	if i < len(c.Sections) {
+		c.Sections[i].Collected = true
+		c.Sections[i].Properties = urls
+	}
+	c.mu.Unlock()
+	if err := c.appendLog(entries...); err != nil {
+		return err
+	}
+	return c.save()
+}
+
+// split separates urls into listings already completed and URLs that
+// still need scraping (pending or failed).
+func (c *Checkpoint) split(urls []string) ([]models.Listing, []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var done []models.Listing
+	var todo []string
+	for _, u := range urls {
+		st, ok := c.Properties[u]
+		if ok && st.Status == PropertyDone {
+			if st.Listing != nil {
+				done = append(done, *st.Listing)
+			}
+			continue
+		}
+		todo = append(todo, u)
+	}
+	return done, todo
+}
+
+// markDone records a scraped property. A listing without a title is
+// stored as done but without data, like the pool drops it.
+func (c *Checkpoint) markDone(u string, l models.Listing) error {
+	st := PropertyState{Status: PropertyDone}
+	if l.Title != "" {
+		st.Listing = &l
+	}
+	return c.setProperty(u, st)
+}
+
+func (c *Checkpoint) markFailed(u string, err error) error {
+	return c.setProperty(u, PropertyState{Status: PropertyFailed, Error: err.Error()})
+}
+
+func (c *Checkpoint) setProperty(u string, st PropertyState) error {
+	c.mu.Lock()
+	c.Properties[u] = &st
+	c.mu.Unlock()
+	return c.appendLog(propertyEntry{URL: u, PropertyState: st})
+}
+
+// appendLog adds entries to the property log, one JSON line each.
+func (c *Checkpoint) appendLog(entries ...propertyEntry) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+
+	c.logMu.Lock()
+	defer c.logMu.Unlock()
+	f, err := os.OpenFile(c.logPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("could not write checkpoint log: %w", err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write checkpoint log: %w", err)
+	}
+	return f.Close()
+}
+
+// Counts returns how many properties are in each state.
+func (c *Checkpoint) Counts() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counts := make(map[string]int)
+	for _, st := range c.Properties {
+		counts[st.Status]++
+	}
+	return counts
+}
+
+// SectionsVisited returns how many sections have had their property
+// URLs collected.
+func (c *Checkpoint) SectionsVisited() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for _, s := range c.Sections {
+		if s.Collected {
+			n++
+		}
+	}
+	return n
+}
+
+// ErrorSummary groups the errors of failed properties by message, most
+// frequent first, e.g. "3× HTTP 429 Too Many Requests; 1× ...". At most
+// max distinct messages are listed.
+func (c *Checkpoint) ErrorSummary(max int) string {
+	c.mu.Lock()
+	counts := make(map[string]int)
+	for _, st := range c.Properties {
+		if st.Status == PropertyFailed {
+			counts[st.Error]++
+		}
+	}
+	c.mu.Unlock()
+
+	msgs := make([]string, 0, len(counts))
+	for m := range counts {
+		msgs = append(msgs, m)
+	}
+	sort.Slice(msgs, func(i, j int) bool {
+		if counts[msgs[i]] != counts[msgs[j]] {
+			return counts[msgs[i]] > counts[msgs[j]]
+		}
+		return msgs[i] < msgs[j]
+	})
+
+	var parts []string
+	for i, m := range msgs {
+		if i == max {
+			parts = append(parts, fmt.Sprintf("%d more", len(msgs)-max))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%d× %s", counts[m], m))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// save writes the sections file atomically (temp file + rename) so a
+// crash mid-write never leaves a truncated file behind. Property
+// outcomes are in the log, not here.
+func (c *Checkpoint) save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.UpdatedAt = time.Now()
+	b, err := json.MarshalIndent(struct {
+		RunID     string          `json:"run_id"`
+		StartedAt time.Time       `json:"started_at"`
+		UpdatedAt time.Time       `json:"updated_at"`
+		Sections  []*SectionState `json:"sections"`
+	}{c.RunID, c.StartedAt, c.UpdatedAt, c.Sections}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return fmt.Errorf("could not write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("could not write checkpoint: %w", err)
+	}
+	return nil
+}
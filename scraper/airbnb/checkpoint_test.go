@@ -0,0 +1,223 @@
+package airbnb
+
+import (
+	"airbnb-scraper/models"
+	"errors"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const (
+	roomA = "https://www.airbnb.com/rooms/1"
+	roomB = "https://www.airbnb.com/rooms/2"
+	roomC = "https://www.airbnb.com/rooms/3"
+	roomD = "https://www.airbnb.com/rooms/4"
+	roomE = "https://www.airbnb.com/rooms/5"
+)
+
+// interruptedRun leaves a checkpoint as a run killed halfway would: one
+// section collected, its properties done, failed, untitled or pending.
+func interruptedRun(t *testing.T, dir string) {
+	t.Helper()
+	c, err := NewCheckpoint(dir, "run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := []error{
+		c.setSections([]string{"https://www.airbnb.com/s/a", "https://www.airbnb.com/s/b"}),
+		c.setSectionProperties(0, []string{roomA, roomB, roomC, roomD}),
+		c.markDone(roomA, models.Listing{ID: 1, Title: "Loft", URL: roomA}),
+		c.markDone(roomB, models.Listing{ID: 2, URL: roomB}),
+		c.markFailed(roomC, errors.New("HTTP 429 Too Many Requests")),
+	}
+	for _, err := range steps {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCheckpointResume(t *testing.T) {
+	dir := t.TempDir()
+	interruptedRun(t, dir)
+
+	if _, err := NewCheckpoint(dir, "run"); err == nil {
+		t.Error("NewCheckpoint overwrote an existing run")
+	}
+	c, err := LoadCheckpoint(dir, "run")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := c.sectionURLs(); !reflect.DeepEqual(got, []string{"https://www.airbnb.com/s/a", "https://www.airbnb.com/s/b"}) {
+		t.Errorf("sectionURLs = %v", got)
+	}
+	if got, ok := c.sectionProperties(0); !ok || !reflect.DeepEqual(got, []string{roomA, roomB, roomC, roomD}) {
+		t.Errorf("sectionProperties(0) = %v, %v", got, ok)
+	}
+	if _, ok := c.sectionProperties(1); ok {
+		t.Error("section 1 reported as collected")
+	}
+	if _, ok := c.sectionProperties(5); ok {
+		t.Error("section 5 reported as collected")
+	}
+	if n := c.SectionsVisited(); n != 1 {
+		t.Errorf("SectionsVisited = %d, want 1", n)
+	}
+
+	want := map[string]int{PropertyDone: 2, PropertyFailed: 1, PropertyPending: 1}
+	if got := c.Counts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Counts = %v, want %v", got, want)
+	}
+	if got := c.ErrorSummary(3); got != "1× HTTP 429 Too Many Requests" {
+		t.Errorf("ErrorSummary = %q", got)
+	}
+}
+
+func TestCheckpointSplit(t *testing.T) {
+	dir := t.TempDir()
+	interruptedRun(t, dir)
+	c, err := LoadCheckpoint(dir, "run")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		urls     []string
+		wantDone []int64
+		wantTodo []string
+	}{
+		{"whole section", []string{roomA, roomB, roomC, roomD}, []int64{1}, []string{roomC, roomD}},
+		{"unknown URL", []string{roomE, roomA}, []int64{1}, []string{roomE}},
+		{"untitled listing is not redone", []string{roomB}, nil, nil},
+		{"nothing", nil, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done, todo := c.split(tt.urls)
+			var ids []int64
+			for _, l := range done {
+				ids = append(ids, l.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantDone) || !reflect.DeepEqual(todo, tt.wantTodo) {
+				t.Errorf("split = %v, %v; want %v, %v", ids, todo, tt.wantDone, tt.wantTodo)
+			}
+		})
+	}
+}
+
+func TestCheckpointSectionListings(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewCheckpoint(dir, "cards")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.setSections([]string{"https://www.airbnb.com/s/a"}); err != nil {
+		t.Fatal(err)
+	}
+	listings := []models.Listing{{ID: 1, Title: "Loft", URL: roomA}, {ID: 2, Title: "Villa", URL: roomB}}
+	if err := c.setSectionListings(0, listings); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = LoadCheckpoint(dir, "cards")
+	if err != nil {
+		t.Fatal(err)
+	}
+	urls, ok := c.sectionProperties(0)
+	if !ok {
+		t.Fatal("section not collected")
+	}
+	done, todo := c.split(urls)
+	if len(todo) != 0 || len(done) != 2 || done[0].Title != "Loft" || done[1].Title != "Villa" {
+		t.Errorf("split = %+v, %v", done, todo)
+	}
+}
+
+// TestCheckpointLog checks that property outcomes are appended to the
+// log rather than rewriting the sections file, and that a line cut short
+// by a crash is ignored.
+func TestCheckpointLog(t *testing.T) {
+	dir := t.TempDir()
+	interruptedRun(t, dir)
+
+	sections, err := os.ReadFile(checkpointPath(dir, "run"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(sections), "Loft") {
+		t.Error("sections file holds listings")
+	}
+	log, err := os.ReadFile(propertyLogPath(dir, "run"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(log), "\n"); n != 3 {
+		t.Errorf("log has %d lines, want 3", n)
+	}
+
+	f, err := os.OpenFile(propertyLogPath(dir, "run"), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"url":"` + roomD + `","status":"do`)
+	f.Close()
+
+	c, err := LoadCheckpoint(dir, "run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{PropertyDone: 2, PropertyFailed: 1, PropertyPending: 1}
+	if got := c.Counts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Counts = %v, want %v", got, want)
+	}
+
+	// A resumed run appends to the same log; the latest outcome wins.
+	if err := c.markDone(roomC, models.Listing{ID: 3, Title: "Villa", URL: roomC}); err != nil {
+		t.Fatal(err)
+	}
+	c, err = LoadCheckpoint(dir, "run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := c.Properties[roomC]; st.Status != PropertyDone || st.Listing == nil || st.Listing.Title != "Villa" {
+		t.Errorf("room C after resume = %+v", st)
+	}
+}
+
+// TestCheckpointLegacy loads a checkpoint written before the property
+// log, with the outcomes inside the sections file.
+func TestCheckpointLegacy(t *testing.T) {
+	dir := t.TempDir()
+	legacy := `{"run_id": "old", "sections": [{"url": "https://www.airbnb.com/s/a", "collected": true, "properties": ["` + roomA + `", "` + roomB + `"]}],
+		"properties": {"` + roomA + `": {"status": "done", "listing": {"id": "1", "title": "Loft", "url": "` + roomA + `"}}, "` + roomB + `": {"status": "pending"}}}`
+	if err := os.WriteFile(checkpointPath(dir, "old"), []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadCheckpoint(dir, "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done, todo := c.split([]string{roomA, roomB})
+	if len(done) != 1 || done[0].Title != "Loft" || !reflect.DeepEqual(todo, []string{roomB}) {
+		t.Errorf("split = %+v, %v", done, todo)
+	}
+}
+
+func TestNewRunID(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		id := NewRunID()
+		if !regexp.MustCompile(`^[0-9]{8}-[0-9]{6}-[0-9a-f]{4}$`).MatchString(id) {
+			t.Fatalf("NewRunID = %q", id)
+		}
+		if seen[id] {
+			t.Fatalf("NewRunID repeated %q", id)
+		}
+		seen[id] = true
+	}
+}
@@ -0,0 +1,218 @@
+package airbnb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// Page is everything an Extractor can read for one detail page: the live
+// tab, the embedded/captured JSON and the search result seen for the room.
+type Page struct {
+	ctx    context.Context
+	data   *structuredData
+	search *searchResult
+}
+
+// Extractor produces the raw string value of one listing field from a
+// page. An empty string with a nil error means "no match here, try the
+// next strategy in the chain".
+type Extractor interface {
+	Name() string
+	Extract(p *Page) (string, error)
+}
+
+// pathExtractor reads values from a structured source (jsonld, state, api).
+type pathExtractor struct {
+	name   string
+	source string
+	paths  []string
+	join   string
+}
+
+func (e *pathExtractor) Name() string { return e.name }
+
+func (e *pathExtractor) Extract(p *Page) (string, error) {
+	if p.data == nil {
+		return "", nil
+	}
+
+	for _, root := range p.data.roots(e.source) {
+		var parts []string
+		for _, path := range e.paths {
+			v, ok := lookupPath(root, path)
+			if !ok {
+				continue
+			}
+			s := valueString(v)
+			if _, isText := v.(string); isText {
+				s = cleanStructured(s)
+			}
+			if s != "" {
+				parts = append(parts, s)
+			}
+			if e.join == "" && len(parts) > 0 {
+				break
+			}
+		}
+		if len(parts) > 0 {
+			return strings.Join(parts, e.join), nil
+		}
+	}
+	return "", nil
+}
+
+// searchExtractor reads a field of the StaysSearch result for the room.
+type searchExtractor struct {
+	name  string
+	field string
+}
+
+func (e *searchExtractor) Name() string { return e.name }
+
+func (e *searchExtractor) Extract(p *Page) (string, error) {
+	if p.search == nil {
+		return "", nil
+	}
+
+	r := p.search
+	switch e.field {
+	case "title":
+		return r.Title, nil
+	case "price":
+		return r.RawPrice, nil
+	case "price_unit":
+		return r.PriceQualifier, nil
+	case "rating":
+		if r.Rating > 0 {
+			return strconv.FormatFloat(r.Rating, 'f', -1, 64), nil
+		}
+	case "review_count":
+		if r.ReviewCount > 0 {
+			return strconv.Itoa(r.ReviewCount), nil
+		}
+	case "coordinates":
+		if r.Latitude != 0 || r.Longitude != 0 {
+			return formatCoordinates(r.Latitude, r.Longitude), nil
+		}
+	case "neighborhood":
+		return r.Neighborhood, nil
+	}
+	return "", nil
+}
+
+// scriptExtractor evaluates JavaScript in the page. A script may return a
+// Promise (e.g. to open a modal and wait for it); its result is awaited.
+// CSS strategies are compiled to a script too, see newCSSExtractor.
+type scriptExtractor struct {
+	name   string
+	script string
+}
+
+func (e *scriptExtractor) Name() string { return e.name }
+
+func (e *scriptExtractor) Extract(p *Page) (string, error) {
+	var v interface{}
+	await := func(params *runtime.EvaluateParams) *runtime.EvaluateParams { return params.WithAwaitPromise(true) }
+	if err := chromedp.Run(p.ctx, chromedp.Evaluate(e.script, &v, await)); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(valueString(v)), nil
+}
+
+func newCSSExtractor(name, selector, attr string) *scriptExtractor {
+	sel, _ := json.Marshal(selector)
+	read := `(el.innerText || el.textContent || '')`
+	if attr != "" {
+		a, _ := json.Marshal(attr)
+		read = `(el.getAttribute(` + string(a) + `) || '')`
+	}
+
+	return &scriptExtractor{
+		name: name,
+		script: `(() => {
+			const el = document.querySelector(` + string(sel) + `);
+			if (!el) return '';
+			return ` + read + `.replace(/\s+/g, ' ').trim();
+		})()`,
+	}
+}
+
+// patternExtractor narrows another extractor's result with a regexp: the
+// first capture group if there is one, otherwise the whole match.
+type patternExtractor struct {
+	Extractor
+	re *regexp.Regexp
+}
+
+func (e *patternExtractor) Extract(p *Page) (string, error) {
+	v, err := e.Extractor.Extract(p)
+	if err != nil || v == "" {
+		return "", err
+	}
+
+	m := e.re.FindStringSubmatch(v)
+	if m == nil {
+		return "", nil
+	}
+	if len(m) > 1 {
+		return strings.TrimSpace(m[1]), nil
+	}
+	return strings.TrimSpace(m[0]), nil
+}
+
+// valueString renders a scalar as text and anything else (lists,
+// objects) as JSON, so list-valued fields can be parsed by their setter.
+func valueString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string, float64, bool:
+		return scalarString(v)
+	case []interface{}:
+		if len(t) == 0 {
+			return ""
+		}
+		b, err := json.Marshal(t)
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+}
+
+// chain is the ordered list of strategies for one field.
+type chain struct {
+	field      string
+	extractors []Extractor
+}
+
+// run returns the first non-empty value and the strategy that produced it.
+// A failing strategy is skipped unless the page context itself is done.
+func (c chain) run(p *Page) (string, string, error) {
+	for _, e := range c.extractors {
+		v, err := e.Extract(p)
+		if err != nil {
+			if p.ctx.Err() != nil {
+				return "", "", fmt.Errorf("%s/%s: %w", c.field, e.Name(), err)
+			}
+			continue
+		}
+		if v != "" {
+			return v, e.Name(), nil
+		}
+	}
+	return "", "", nil
+}
@@ -0,0 +1,100 @@
+package airbnb
+
+import (
+	"airbnb-scraper/models"
+	"strings"
+)
+
+// listingField maps a selector-file field name onto models.Listing.
+type listingField struct {
+	name string
+	set  func(l *models.Listing, raw string)
+}
+
+// listingFields are the fields a selector file may define, in the order
+// their chains run.
+var listingFields = []listingField{
+	{"title", func(l *models.Listing, raw string) { l.Title = raw }},
+	{"price", func(l *models.Listing, raw string) {
+		l.RawPrice = raw
+		l.Price = parsePrice(raw)
+	}},
+	{"price_unit", setPriceUnit},
+	{"stay_dates", func(l *models.Listing, raw string) { l.CheckIn, l.CheckOut = parseStayDates(raw) }},
+	{"price_breakdown", setBreakdown},
+	{"location", func(l *models.Listing, raw string) { l.Location = raw }},
+	{"coordinates", func(l *models.Listing, raw string) { l.Latitude, l.Longitude = parseCoordinates(raw) }},
+	{"neighborhood", func(l *models.Listing, raw string) { l.Neighborhood = raw }},
+	{"city", func(l *models.Listing, raw string) { l.City = raw }},
+	{"region", func(l *models.Listing, raw string) { l.Region = raw }},
+	{"country", func(l *models.Listing, raw string) { l.Country = raw }},
+	{"rating", func(l *models.Listing, raw string) { l.Rating = parseRating(raw) }},
+	{"review_count", func(l *models.Listing, raw string) { l.ReviewCount = parseCount(raw) }},
+	{"category_scores", func(l *models.Listing, raw string) { l.Scores = parseCategoryScores(raw) }},
+	{"description", func(l *models.Listing, raw string) { l.Description = truncate(raw, 200) }},
+	{"room_type", func(l *models.Listing, raw string) { l.RoomType, _ = listingKind(raw) }},
+	{"property_type", func(l *models.Listing, raw string) { _, l.PropertyType = listingKind(raw) }},
+	{"guests", func(l *models.Listing, raw string) { l.Guests = parseCount(raw) }},
+	{"bedrooms", func(l *models.Listing, raw string) { l.Bedrooms = parseCount(raw) }},
+	{"beds", func(l *models.Listing, raw string) { l.Beds = parseCount(raw) }},
+	{"bathrooms", func(l *models.Listing, raw string) { l.Bathrooms, l.SharedBath = parseBathrooms(raw) }},
+	{"amenities", func(l *models.Listing, raw string) { l.Amenities = parseAmenities(raw) }},
+	{"host_id", func(l *models.Listing, raw string) { l.Host.ID = parseHostID(raw) }},
+	{"host_name", func(l *models.Listing, raw string) { l.Host.Name = raw }},
+	{"host_superhost", func(l *models.Listing, raw string) { l.Host.Superhost = parseFlag(raw) }},
+	{"host_years", func(l *models.Listing, raw string) { l.Host.YearsHosting = parseCount(raw) }},
+	{"host_response_rate", func(l *models.Listing, raw string) { l.Host.ResponseRate = parseCount(raw) }},
+	{"host_response_time", func(l *models.Listing, raw string) { l.Host.ResponseTime = strings.ToLower(raw) }},
+	{"host_listings", func(l *models.Listing, raw string) { l.Host.ListingCount = parseCount(raw) }},
+	{"host_professional", func(l *models.Listing, raw string) { l.Host.Professional = parseFlag(raw) }},
+	{"house_rules", setHouseRules},
+	{"safety", setSafety},
+	{"cancellation_policy", setCancellation},
+	{"photos", func(l *models.Listing, raw string) { l.Photos = parsePhotos(raw) }},
+}
+
+func fieldByName(name string) *listingField {
+	for i := range listingFields {
+		if listingFields[i].name == name {
+			return &listingFields[i]
+		}
+	}
+	return nil
+}
+
+// Extract runs every field chain against the page and returns the
+// listing, with Sources naming the strategy that filled each field.
+func (set *SelectorSet) Extract(p *Page) (models.Listing, error) {
+	listing := models.Listing{Sources: make(map[string]string)}
+
+	for _, c := range set.chains {
+		raw, strategy, err := c.run(p)
+		if err != nil {
+			return models.Listing{}, err
+		}
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		fieldByName(c.field).set(&listing, raw)
+		listing.Sources[c.field] = strategy
+	}
+	fillPlace(&listing)
+	fillPricing(&listing)
+
+	return listing, nil
+}
+
+// formatSources renders Listing.Sources as "title=jsonld-name price=aria-total ...",
+// showing fields that no strategy could fill as "-".
+func (set *SelectorSet) formatSources(sources map[string]string) string {
+	parts := make([]string, len(set.chains))
+	for i, c := range set.chains {
+		src := sources[c.field]
+		if src == "" {
+			src = "-"
+		}
+		parts[i] = c.field + "=" + src
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,297 @@
+package airbnb
+
+import (
+	"airbnb-scraper/config"
+	"airbnb-scraper/utils"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/chromedp"
+)
+
+// fixtureStore keeps rendered pages and captured API bodies on disk so a
+// run can be replayed offline. Files are named after the path and query of
+// the page URL, so a recording replays under any base URL:
+//
+//	room-<id>-<hash>.html / .api.json   detail pages
+//	page-<hash>.html / .api.json        homepage and search pages
+//	index.json                          path → file name, for humans
+//
+// The recording origin is replaced by originToken in everything saved and
+// put back as the configured origin on replay; fixtures recorded against a
+// local mock never carry its port.
+type fixtureStore struct {
+	dir    string
+	origin *url.URL // origin of cfg.BaseURL; replay maps local paths back onto it
+
+	mu    sync.Mutex
+	index map[string]string
+
+	server   *http.Server
+	localURL *url.URL // replay server origin
+}
+
+func newFixtureStore(dir, baseURL string) (*fixtureStore, error) {
+	origin, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create fixtures dir: %w", err)
+	}
+
+	f := &fixtureStore{
+		dir:    dir,
+		origin: &url.URL{Scheme: origin.Scheme, Host: origin.Host},
+		index:  make(map[string]string),
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, "index.json")); err == nil {
+		json.Unmarshal(b, &f.index)
+	}
+	return f, nil
+}
+
+// originToken stands in for the recording origin inside saved fixtures.
+const originToken = "{{fixture-origin}}"
+
+// portable replaces the store's origin in b with originToken.
+func (f *fixtureStore) portable(b []byte) []byte {
+	return bytes.ReplaceAll(b, []byte(f.origin.String()), []byte(originToken))
+}
+
+// restore is the inverse of portable for the store's (replay) origin.
+func (f *fixtureStore) restore(b []byte) []byte {
+	return bytes.ReplaceAll(b, []byte(originToken), []byte(f.origin.String()))
+}
+
+// path is pageURL without scheme and host, the part fixtures are keyed on.
+func (f *fixtureStore) path(pageURL string) string {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return pageURL
+	}
+	return u.RequestURI()
+}
+
+func (f *fixtureStore) key(pageURL string) string {
+	sum := sha1.Sum([]byte(f.path(pageURL)))
+	hash := hex.EncodeToString(sum[:])[:12]
+	if id := roomIDString(pageURL); id != "" {
+		return "room-" + id + "-" + hash
+	}
+	return "page-" + hash
+}
+
+// savePage writes the current DOM of the tab as the fixture for pageURL.
+func (f *fixtureStore) savePage(ctx context.Context, pageURL string) error {
+	var doc string
+	err := chromedp.Run(ctx, chromedp.Evaluate(
+		`'<!DOCTYPE html>\n' + document.documentElement.outerHTML`, &doc))
+	if err != nil {
+		return fmt.Errorf("could not read page HTML: %w", err)
+	}
+
+	key := f.key(pageURL)
+	if err := os.WriteFile(filepath.Join(f.dir, key+".html"), f.portable([]byte(doc)), 0644); err != nil {
+		return fmt.Errorf("could not write fixture: %w", err)
+	}
+	return f.addToIndex(pageURL, key)
+}
+
+// apiFixture is the on-disk form of one captured API response.
+type apiFixture struct {
+	Operation string          `json:"operation"`
+	URL       string          `json:"url"`
+	Body      json.RawMessage `json:"body"`
+}
+
+// saveAPI writes the responses captured so far as the API fixture for pageURL.
+func (f *fixtureStore) saveAPI(pageURL string, c *apiCapture) error {
+	c.mu.Lock()
+	var out []apiFixture
+	for _, r := range c.responses {
+		if json.Valid(r.Body) {
+			out = append(out, apiFixture{
+				Operation: r.Operation,
+				URL:       string(f.portable([]byte(r.URL))),
+				Body:      f.portable(r.Body),
+			})
+		}
+	}
+	c.mu.Unlock()
+
+	if len(out) == 0 {
+		return nil
+	}
+
+	b, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	key := f.key(pageURL)
+	if err := os.WriteFile(filepath.Join(f.dir, key+".api.json"), b, 0644); err != nil {
+		return fmt.Errorf("could not write API fixture: %w", err)
+	}
+	return f.addToIndex(pageURL, key)
+}
+
+// loadAPI returns the API responses recorded for pageURL, if any.
+func (f *fixtureStore) loadAPI(pageURL string) []apiResponse {
+	b, err := os.ReadFile(filepath.Join(f.dir, f.key(pageURL)+".api.json"))
+	if err != nil {
+		return nil
+	}
+
+	var saved []apiFixture
+	if err := json.Unmarshal(b, &saved); err != nil {
+		return nil
+	}
+	out := make([]apiResponse, len(saved))
+	for i, r := range saved {
+		out[i] = apiResponse{
+			Operation: r.Operation,
+			URL:       string(f.restore([]byte(r.URL))),
+			Body:      f.restore(r.Body),
+		}
+	}
+	return out
+}
+
+func (f *fixtureStore) addToIndex(pageURL, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p := f.path(pageURL)
+	if f.index[p] == key {
+		return nil
+	}
+	f.index[p] = key
+	b, err := json.MarshalIndent(f.index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(f.dir, "index.json"), b, 0644)
+}
+
+// serve starts the replay server on a random local port. Any request is
+// mapped back onto the original origin (path + query unchanged) and
+// answered with the matching fixture, so links inside replayed pages,
+// such as the search "Next" button, keep working.
+func (f *fixtureStore) serve() error {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("could not start fixture server: %w", err)
+	}
+
+	f.localURL = &url.URL{Scheme: "http", Host: ln.Addr().String()}
+	f.server = &http.Server{Handler: http.HandlerFunc(f.handle)}
+	go f.server.Serve(ln)
+	return nil
+}
+
+func (f *fixtureStore) handle(w http.ResponseWriter, r *http.Request) {
+	original := f.origin.String() + r.URL.RequestURI()
+	b, err := os.ReadFile(filepath.Join(f.dir, f.key(original)+".html"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(stripScripts(f.restore(b)))
+}
+
+// replayURL rewrites pageURL onto the replay server.
+func (f *fixtureStore) replayURL(pageURL string) string {
+	u, err := url.Parse(pageURL)
+	if err != nil || f.localURL == nil {
+		return pageURL
+	}
+	u.Scheme, u.Host = f.localURL.Scheme, f.localURL.Host
+	return u.String()
+}
+
+// pageURL is the address the tab should load for u: u itself, or its
+// fixture on the local server in replay mode.
+func (s *Scraper) pageURL(u string) string {
+	if s.cfg.FixturesMode == config.FixturesReplay {
+		return s.fixtures.replayURL(u)
+	}
+	return u
+}
+
+// recordPage saves the tab's current DOM as the fixture for u when
+// recording. Failures only warn; recording must never break a live run.
+func (s *Scraper) recordPage(ctx context.Context, u string) {
+	if s.cfg.FixturesMode != config.FixturesRecord {
+		return
+	}
+	if err := s.fixtures.savePage(ctx, u); err != nil {
+		utils.Warn("Fixture not saved for %s: %v", u, err)
+	}
+}
+
+// recordCurrentPage records the tab under its current address, for pages
+// reached by clicking rather than navigating (search pagination).
+func (s *Scraper) recordCurrentPage(ctx context.Context) {
+	if s.cfg.FixturesMode != config.FixturesRecord {
+		return
+	}
+	var href string
+	if err := chromedp.Run(ctx, chromedp.Evaluate(`location.href`, &href)); err != nil {
+		utils.Warn("Fixture not saved: %v", err)
+		return
+	}
+	s.recordPage(ctx, href)
+}
+
+// recordAPI saves the responses captured for u when recording.
+func (s *Scraper) recordAPI(u string, c *apiCapture) {
+	if s.cfg.FixturesMode != config.FixturesRecord {
+		return
+	}
+	if err := s.fixtures.saveAPI(u, c); err != nil {
+		utils.Warn("API fixture not saved for %s: %v", u, err)
+	}
+}
+
+// replayAPI feeds the API responses recorded for u into c when replaying,
+// standing in for the network traffic a live page would produce.
+func (s *Scraper) replayAPI(u string, c *apiCapture) {
+	if s.cfg.FixturesMode != config.FixturesReplay {
+		return
+	}
+	c.add(s.fixtures.loadAPI(u))
+}
+
+func (f *fixtureStore) close() {
+	if f.server != nil {
+		f.server.Close()
+	}
+}
+
+var scriptTag = regexp.MustCompile(`(?is)<script\b([^>]*)>.*?</script>`)
+
+// stripScripts removes executable scripts from a saved page so replay
+// cannot call out to Airbnb, keeping JSON data blocks for the extractors.
+func stripScripts(doc []byte) []byte {
+	return scriptTag.ReplaceAllFunc(doc, func(tag []byte) []byte {
+		attrs := strings.ToLower(string(scriptTag.FindSubmatch(tag)[1]))
+		if strings.Contains(attrs, "application/json") || strings.Contains(attrs, "application/ld+json") {
+			return tag
+		}
+		return nil
+	})
+}
@@ -0,0 +1,189 @@
+package airbnb
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestStripScripts(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain script", `<p>a</p><script>fetch("/api")</script><p>b</p>`, `<p>a</p><p>b</p>`},
+		{"external script", `<script src="https://a0.muscache.com/app.js"></script>`, ``},
+		{"module script", `<script type="module">import "x"</script>`, ``},
+		{"uppercase tag", `<SCRIPT>alert(1)</SCRIPT>`, ``},
+		{"multiline body", "<script>\nvar a = 1;\nvar b = 2;\n</script>", ``},
+		{"json data", `<script id="data-deferred-state-0" type="application/json">{"a":1}</script>`, `<script id="data-deferred-state-0" type="application/json">{"a":1}</script>`},
+		{"ld+json", `<script type="application/ld+json">{"@type":"Product"}</script>`, `<script type="application/ld+json">{"@type":"Product"}</script>`},
+		{"json type in caps", `<script TYPE="Application/JSON">{}</script>`, `<script TYPE="Application/JSON">{}</script>`},
+		{"mixed", `<script>x()</script><script type="application/json">[]</script><script>y()</script>`, `<script type="application/json">[]</script>`},
+		{"no scripts", `<div>hello</div>`, `<div>hello</div>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(stripScripts([]byte(tt.in))); got != tt.want {
+				t.Errorf("stripScripts(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFixtureKey(t *testing.T) {
+	f := &fixtureStore{}
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://www.airbnb.com/rooms/41001101", `^room-41001101-[0-9a-f]{12}$`},
+		{"https://www.airbnb.com/rooms/plus/52345?adults=2", `^room-52345-[0-9a-f]{12}$`},
+		{"https://www.airbnb.com/", `^page-[0-9a-f]{12}$`},
+		{"https://www.airbnb.com/s/Bali/homes?page=2", `^page-[0-9a-f]{12}$`},
+		{"https://www.airbnb.com/users/show/9003", `^page-[0-9a-f]{12}$`},
+	}
+	for _, tt := range tests {
+		if got := f.key(tt.url); !regexp.MustCompile(tt.want).MatchString(got) {
+			t.Errorf("key(%q) = %q, want match for %s", tt.url, got, tt.want)
+		}
+	}
+
+	const room = "https://www.airbnb.com/rooms/41001101"
+	if f.key(room) != f.key(room) {
+		t.Error("key is not stable")
+	}
+	if f.key(room) == f.key(room+"?check_in=2026-11-02") {
+		t.Error("key ignores the query string")
+	}
+	if f.key(room) != f.key("http://127.0.0.1:43257/rooms/41001101") {
+		t.Error("key depends on the origin")
+	}
+}
+
+// TestFixtureOrigin records against a local mock origin and replays under
+// the live one: nothing saved may mention the mock's host and port.
+func TestFixtureOrigin(t *testing.T) {
+	dir := t.TempDir()
+	rec, err := newFixtureStore(dir, "http://127.0.0.1:43257/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const mockRoom = "http://127.0.0.1:43257/rooms/41001101"
+	page := `<img src="http://127.0.0.1:43257/im/pictures/41001101/01.jpg">`
+	if err := os.WriteFile(filepath.Join(dir, rec.key(mockRoom)+".html"), rec.portable([]byte(page)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := &apiCapture{responses: []apiResponse{{
+		Operation: opStaysPdpSections,
+		URL:       "http://127.0.0.1:43257/api/v3/StaysPdpSections",
+		Body:      []byte(`{"picture":"http://127.0.0.1:43257/im/pictures/41001101/02.jpg"}`),
+	}}}
+	if err := rec.saveAPI(mockRoom, c); err != nil {
+		t.Fatal(err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*"))
+	for _, name := range files {
+		b, _ := os.ReadFile(name)
+		if bytes.Contains(b, []byte("127.0.0.1")) {
+			t.Errorf("%s holds the recording origin:\n%s", filepath.Base(name), b)
+		}
+	}
+
+	replay, err := newFixtureStore(dir, "https://www.airbnb.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(replay.handle))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/rooms/41001101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if want := `<img src="https://www.airbnb.com/im/pictures/41001101/01.jpg">`; string(got) != want {
+		t.Errorf("replayed page = %q, want %q", got, want)
+	}
+
+	api := replay.loadAPI("https://www.airbnb.com/rooms/41001101")
+	if len(api) != 1 {
+		t.Fatalf("loadAPI = %+v", api)
+	}
+	if want := "https://www.airbnb.com/api/v3/StaysPdpSections"; api[0].URL != want {
+		t.Errorf("loadAPI URL = %q, want %q", api[0].URL, want)
+	}
+	if want := "https://www.airbnb.com/im/pictures/41001101/02.jpg"; !bytes.Contains(api[0].Body, []byte(want)) {
+		t.Errorf("loadAPI body = %s, want it to contain %s", api[0].Body, want)
+	}
+}
+
+// TestFixtureReplay checks that replay serves a saved page under its
+// original path, scripts stripped, and hands back its API responses.
+func TestFixtureReplay(t *testing.T) {
+	f, err := newFixtureStore(t.TempDir(), "https://www.airbnb.com/s/homes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const room = "https://www.airbnb.com/rooms/41001101?adults=2"
+	saved := `<html><script>boom()</script><script type="application/json">{"id":1}</script></html>`
+	if err := os.WriteFile(filepath.Join(f.dir, f.key(room)+".html"), []byte(saved), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := &apiCapture{responses: []apiResponse{{Operation: opStaysPdpSections, URL: "https://www.airbnb.com/api/v3/StaysPdpSections", Body: []byte(`{"data":{}}`)}}}
+	if err := f.saveAPI(room, c); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(f.handle))
+	defer srv.Close()
+	f.localURL, _ = url.Parse(srv.URL)
+
+	local := f.replayURL(room)
+	if want := srv.URL + "/rooms/41001101?adults=2"; local != want {
+		t.Fatalf("replayURL = %q, want %q", local, want)
+	}
+
+	resp, err := http.Get(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if want := `<html><script type="application/json">{"id":1}</script></html>`; string(page) != want {
+		t.Errorf("replayed page = %q, want %q", page, want)
+	}
+
+	resp, err = http.Get(srv.URL + "/rooms/999")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("missing fixture: status %d, want 404", resp.StatusCode)
+	}
+
+	got := f.loadAPI(room)
+	if len(got) != 1 || got[0].Operation != opStaysPdpSections {
+		t.Fatalf("loadAPI = %+v", got)
+	}
+	var body bytes.Buffer
+	if err := json.Compact(&body, got[0].Body); err != nil || body.String() != `{"data":{}}` {
+		t.Errorf("loadAPI body = %s", got[0].Body)
+	}
+	if f.loadAPI("https://www.airbnb.com/rooms/999") != nil {
+		t.Error("loadAPI returned responses for an unrecorded page")
+	}
+}
@@ -0,0 +1,75 @@
+package airbnb
+
+import (
+	"airbnb-scraper/models"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var coordinatesPattern = regexp.MustCompile(`(-?[0-9]{1,3}(?:\.[0-9]+)?)\s*,\s*(-?[0-9]{1,3}(?:\.[0-9]+)?)`)
+
+// parseCoordinates reads "lat,lng" as produced by the default selector
+// set (joined structured values, the search result, or a map link's
+// ll=/center=/@ parameter). Out-of-range or 0,0 pairs count as unknown.
+func parseCoordinates(raw string) (float64, float64) {
+	m := coordinatesPattern.FindStringSubmatch(raw)
+	if m == nil {
+		return 0, 0
+	}
+	lat, _ := strconv.ParseFloat(m[1], 64)
+	lng, _ := strconv.ParseFloat(m[2], 64)
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 || (lat == 0 && lng == 0) {
+		return 0, 0
+	}
+	return lat, lng
+}
+
+func formatCoordinates(lat, lng float64) string {
+	return strconv.FormatFloat(lat, 'f', -1, 64) + "," + strconv.FormatFloat(lng, 'f', -1, 64)
+}
+
+// fillPlace completes the place fields from each other once every chain
+// has run: parts missing from structured data are split from Location
+// ("[neighborhood,] city, [region,] country"), and an empty Location is
+// rebuilt from the parts.
+func fillPlace(l *models.Listing) {
+	var parts []string
+	for _, p := range strings.Split(l.Location, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+
+	fill := func(dst *string, v string) {
+		if *dst == "" {
+			*dst = v
+		}
+	}
+	switch n := len(parts); {
+	case n == 1:
+		fill(&l.City, parts[0])
+	case n == 2:
+		fill(&l.City, parts[0])
+		fill(&l.Country, parts[1])
+	case n == 3:
+		fill(&l.City, parts[0])
+		fill(&l.Region, parts[1])
+		fill(&l.Country, parts[2])
+	case n >= 4:
+		fill(&l.Neighborhood, parts[0])
+		fill(&l.City, parts[1])
+		fill(&l.Region, parts[n-2])
+		fill(&l.Country, parts[n-1])
+	}
+
+	if l.Location == "" {
+		var place []string
+		for _, p := range []string{l.City, l.Region, l.Country} {
+			if p != "" {
+				place = append(place, p)
+			}
+		}
+		l.Location = strings.Join(place, ", ")
+	}
+}
@@ -0,0 +1,110 @@
+package airbnb
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseCoordinates(t *testing.T) {
+	tests := []struct {
+		raw      string
+		lat, lng float64
+	}{
+		{"3.1466,101.7108", 3.1466, 101.7108},
+		{"3.1466, 101.7108", 3.1466, 101.7108},
+		{"-33.8688,151.2093", -33.8688, 151.2093},
+		{"ll=3.1466,101.7108&z=15", 3.1466, 101.7108},
+		{"@40.7128,-74.006,14z", 40.7128, -74.006},
+		{"0,101.7108", 0, 101.7108},
+		{"90,180", 90, 180},
+		{"0,0", 0, 0},
+		{"0.0,0.0", 0, 0},
+		{"91,101.7108", 0, 0},
+		{"3.1466,181", 0, 0},
+		{"-90.5,10", 0, 0},
+		{"1234.5,10", 0, 0},
+		{"3.1466", 0, 0},
+		{"101.7108", 0, 0},
+		{"", 0, 0},
+		{"near the KLCC park", 0, 0},
+	}
+	for _, tt := range tests {
+		lat, lng := parseCoordinates(tt.raw)
+		if lat != tt.lat || lng != tt.lng {
+			t.Errorf("parseCoordinates(%q) = %v, %v, want %v, %v", tt.raw, lat, lng, tt.lat, tt.lng)
+		}
+	}
+}
+
+// TestCoordinatesExtraction runs the default selector set's structured
+// strategies (paths joined with ",") and the search result through
+// parseCoordinates, the way the coordinates field is filled.
+func TestCoordinatesExtraction(t *testing.T) {
+	jsonld := &pathExtractor{name: "jsonld-geo", source: sourceJSONLD, paths: []string{"latitude", "longitude"}, join: ","}
+	state := &pathExtractor{
+		name:   "state-location-section-geo",
+		source: sourceState,
+		paths:  []string{"**.section[__typename=LocationSection].lat", "**.section[__typename=LocationSection].lng"},
+		join:   ",",
+	}
+
+	tests := []struct {
+		name     string
+		ex       Extractor
+		blob     string
+		lat, lng float64
+	}{
+		{"numbers", jsonld, `{"latitude": 3.1466, "longitude": 101.7108}`, 3.1466, 101.7108},
+		{"string-encoded", jsonld, `{"latitude": "3.1466", "longitude": "101.7108"}`, 3.1466, 101.7108},
+		{"string-encoded with spaces", jsonld, `{"latitude": " -33.8688 ", "longitude": " 151.2093 "}`, -33.8688, 151.2093},
+		{"missing latitude", jsonld, `{"longitude": 101.7108}`, 0, 0},
+		{"missing longitude", jsonld, `{"latitude": 3.1466}`, 0, 0},
+		{"missing both", jsonld, `{"name": "Skyline studio"}`, 0, 0},
+		{"null latitude", jsonld, `{"latitude": null, "longitude": 101.7108}`, 0, 0},
+		{"empty strings", jsonld, `{"latitude": "", "longitude": ""}`, 0, 0},
+		{"zero", jsonld, `{"latitude": 0, "longitude": 0}`, 0, 0},
+		{"latitude out of range", jsonld, `{"latitude": 95.2, "longitude": 101.7108}`, 0, 0},
+		{"longitude out of range", jsonld, `{"latitude": 3.1466, "longitude": -200}`, 0, 0},
+		{"state section", state, `{"sections": [{"section": {"__typename": "LocationSection", "lat": 3.139, "lng": "101.6869"}}]}`, 3.139, 101.6869},
+		{"state section of another type", state, `{"sections": [{"section": {"__typename": "MapSection", "lat": 3.139, "lng": 101.6869}}]}`, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var root interface{}
+			if err := json.Unmarshal([]byte(tt.blob), &root); err != nil {
+				t.Fatal(err)
+			}
+			data := &structuredData{jsonLD: []interface{}{root}, state: []interface{}{root}}
+			raw, err := tt.ex.Extract(&Page{data: data})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if lat, lng := parseCoordinates(raw); lat != tt.lat || lng != tt.lng {
+				t.Errorf("coordinates from %s (raw %q) = %v, %v, want %v, %v", tt.blob, raw, lat, lng, tt.lat, tt.lng)
+			}
+		})
+	}
+}
+
+func TestSearchCoordinates(t *testing.T) {
+	ex := &searchExtractor{name: "search-coordinates", field: "coordinates"}
+	tests := []struct {
+		name     string
+		result   searchResult
+		lat, lng float64
+	}{
+		{"both set", searchResult{Latitude: 3.1466, Longitude: 101.7108}, 3.1466, 101.7108},
+		{"on the equator", searchResult{Longitude: 101.7108}, 0, 101.7108},
+		{"unset", searchResult{}, 0, 0},
+		{"out of range", searchResult{Latitude: 3.1466, Longitude: 250}, 0, 0},
+	}
+	for _, tt := range tests {
+		raw, err := ex.Extract(&Page{search: &tt.result})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lat, lng := parseCoordinates(raw); lat != tt.lat || lng != tt.lng {
+			t.Errorf("%s: coordinates from %q = %v, %v, want %v, %v", tt.name, raw, lat, lng, tt.lat, tt.lng)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package airbnb
+
+import (
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// professionalListings is the listing count from which a host counts as
+// professional even without Airbnb's "Professional host" label: nobody
+// rents out ten homes of their own.
+const professionalListings = 10
+
+// companyName matches host names that are clearly businesses.
+var companyName = regexp.MustCompile(`(?i)\b(ltd|llc|inc|gmbh|sdn\.? bhd|pte|properties|property|management|apartments|suites|hospitality|rentals|residences|group)\b`)
+
+var hostDigits = regexp.MustCompile(`[0-9]+`)
+
+// demandUserPrefix starts the decoded global ID of an Airbnb user.
+const demandUserPrefix = "DemandUser:"
+
+// parseHostID reads a host ID from a plain number, a profile URL, or the
+// API's base64 global ID ("RGVtYW5kVXNlcjo0NTY=" is "DemandUser:456").
+// Plain numbers are tried first: many are valid base64 as well.
+func parseHostID(raw string) int64 {
+	raw = strings.TrimSpace(raw)
+	if raw != "" && strings.Trim(raw, "0123456789") == "" {
+		return parseID(raw)
+	}
+	if decoded, err := base64.StdEncoding.DecodeString(raw); err == nil {
+		if id, ok := strings.CutPrefix(string(decoded), demandUserPrefix); ok {
+			raw = id
+		}
+	}
+	return parseID(hostDigits.FindString(raw))
+}
+
+// parseID reads a decimal ID; anything else, including an ID too large
+// for int64, is 0.
+func parseID(digits string) int64 {
+	id, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// parseFlag reads a boolean field: "true" from structured data, or any
+// text a script returned for a label it found.
+func parseFlag(raw string) bool {
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		return raw != ""
+	}
+	return v
+}
+
+// completeHost fills in what the detail page does not show: the host's
+// listing count, read once per host and run from their profile page
+// (when cfg.HostProfiles is set), and whether the host is professional.
+// Profile failures only warn.
+func (s *Scraper) completeHost(ctx context.Context, h *models.Host) {
+	if h.ID != 0 && h.ListingCount == 0 && s.cfg.HostProfiles && ctx.Err() == nil {
+		count, err := s.hostListingCount(h.ID)
+		if err != nil {
+			utils.Warn("Host %d profile: %v", h.ID, err)
+		}
+		h.ListingCount = count
+	}
+
+	h.Professional = h.Professional ||
+		h.ListingCount >= professionalListings ||
+		companyName.MatchString(h.Name)
+}
+
+// hostLookup is one host's profile visit. Workers asking for the same
+// host wait on once instead of opening the profile again.
+type hostLookup struct {
+	once  sync.Once
+	count int
+	err   error
+}
+
+// hostListingCount returns how many listings a host has, from their
+// profile page. Each host's profile is opened at most once per run, even
+// when several workers reach the host at the same time; failed lookups
+// are not retried, so a profile that does not load is only tried once.
+func (s *Scraper) hostListingCount(hostID int64) (int, error) {
+	s.mu.Lock()
+	lookup, ok := s.hostListings[hostID]
+	if !ok {
+		lookup = &hostLookup{}
+		s.hostListings[hostID] = lookup
+	}
+	s.mu.Unlock()
+
+	lookup.once.Do(func() {
+		lookup.count, lookup.err = s.scrapeHostProfile(hostID)
+	})
+	return lookup.count, lookup.err
+}
+
+func (s *Scraper) scrapeHostProfile(hostID int64) (int, error) {
+	tabCtx, tabCancel := chromedp.NewContext(s.allocCtx)
+	defer tabCancel()
+
+	ctx, cancel := context.WithTimeout(tabCtx, s.cfg.RequestTimeout)
+	defer cancel()
+
+	profileURL := fmt.Sprintf("%s/users/show/%d", s.origin(), hostID)
+	if err := navigate(ctx, s.pageURL(profileURL)); err != nil {
+		return 0, err
+	}
+
+	var raw string
+	err := chromedp.Run(ctx,
+		utils.HideWebDriver(),
+		chromedp.WaitVisible(`body`, chromedp.ByQuery),
+		chromedp.Sleep(2*time.Second),
+		chromedp.Evaluate(`(() => {
+			const text = document.body.innerText || '';
+			const total = text.match(/(?:View|Show) all\s+([0-9,]+)\s+listings/i) || text.match(/([0-9,]+)\s+listings?\b/i);
+			if (total) return total[1];
+			const rooms = new Set(Array.from(document.querySelectorAll('a[href*="/rooms/"]'))
+				.map(a => (a.getAttribute('href').match(/\/rooms\/([0-9]+)/) || [])[1])
+				.filter(Boolean));
+			return rooms.size ? String(rooms.size) : '';
+		})()`, &raw),
+	)
+	if err != nil {
+		return 0, err
+	}
+	s.recordPage(ctx, profileURL)
+
+	return parseCount(raw), nil
+}
@@ -0,0 +1,34 @@
+package airbnb
+
+import "testing"
+
+func TestParseHostID(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want int64
+	}{
+		{"456", 456},
+		{" 10000006 ", 10000006},
+		{"0", 0},
+		// Digit strings whose length is a multiple of 4 are valid base64
+		// too; they must be read as numbers.
+		{"1234", 1234},
+		{"10000000", 10000000},
+		{"123456789012", 123456789012},
+		{"9223372036854775807", 9223372036854775807},
+		{"99999999999999999999", 0}, // overflows int64
+		{"+5", 5},
+		{"-5", 5},
+		{"RGVtYW5kVXNlcjo0NTY=", 456},
+		{" RGVtYW5kVXNlcjo5MjIzMzcyMDM2ODU0Nzc1ODA3 ", 9223372036854775807},
+		{"https://www.airbnb.com/users/show/98765", 98765},
+		{"/users/profile/1234567890123", 1234567890123},
+		{"", 0},
+		{"Hosted by Anna", 0},
+	}
+	for _, tt := range tests {
+		if got := parseHostID(tt.raw); got != tt.want {
+			t.Errorf("parseHostID(%q) = %d, want %d", tt.raw, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package airbnb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// Airbnb GraphQL operations the scraper listens for. Requests look like
+// /api/v3/StaysSearch/<hash>?operationName=StaysSearch&...
+const (
+	opStaysSearch      = "StaysSearch"
+	opStaysPdpSections = "StaysPdpSections"
+	opStaysPdpReviews  = "StaysPdpReviewsQuery"
+
+	opPdpAvailabilityCalendar = "PdpAvailabilityCalendar"
+)
+
+const sourceAPI = "api" // StaysPdpSections body of the detail page itself
+
+// apiResponse is one captured API response body.
+type apiResponse struct {
+	Operation string
+	URL       string
+	Body      []byte
+}
+
+// apiCapture collects the bodies of selected Airbnb API responses seen
+// by one tab. It hooks the DevTools network domain: responseReceived
+// marks a request as interesting, loadingFinished fetches its body.
+type apiCapture struct {
+	mu        sync.Mutex
+	pending   map[network.RequestID]apiResponse
+	responses []apiResponse
+	arrived   chan string
+}
+
+// captureAPI starts listening on the tab behind ctx for the given
+// operations. Call it before the first chromedp.Run on that tab.
+func captureAPI(ctx context.Context, operations ...string) *apiCapture {
+	c := &apiCapture{
+		pending: make(map[network.RequestID]apiResponse),
+		arrived: make(chan string, 64),
+	}
+
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		switch ev := ev.(type) {
+		case *network.EventResponseReceived:
+			if ev.Response == nil {
+				return
+			}
+			for _, op := range operations {
+				if strings.Contains(ev.Response.URL, "/api/v3/"+op) {
+					c.mu.Lock()
+					c.pending[ev.RequestID] = apiResponse{Operation: op, URL: ev.Response.URL}
+					c.mu.Unlock()
+					return
+				}
+			}
+
+		case *network.EventLoadingFinished:
+			c.mu.Lock()
+			resp, ok := c.pending[ev.RequestID]
+			delete(c.pending, ev.RequestID)
+			c.mu.Unlock()
+			if !ok {
+				return
+			}
+
+			// CDP commands must not run inside the listener (deadlock),
+			// so the body is fetched from a goroutine.
+			go func(id network.RequestID) {
+				tctx := cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Target)
+				body, err := network.GetResponseBody(id).Do(tctx)
+				if err != nil {
+					return
+				}
+				resp.Body = body
+
+				c.mu.Lock()
+				c.responses = append(c.responses, resp)
+				c.mu.Unlock()
+
+				select {
+				case c.arrived <- resp.Operation:
+				default:
+				}
+			}(ev.RequestID)
+		}
+	})
+
+	return c
+}
+
+// wait blocks until a response for operation has been captured, the
+// timeout passes or ctx is done. It reports whether one arrived.
+func (c *apiCapture) wait(ctx context.Context, operation string, timeout time.Duration) bool {
+	if c.has(operation) {
+		return true
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case op := <-c.arrived:
+			if op == operation {
+				return true
+			}
+		case <-timer.C:
+			return c.has(operation)
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// add records responses that did not come from the network (fixture replay).
+func (c *apiCapture) add(responses []apiResponse) {
+	c.mu.Lock()
+	c.responses = append(c.responses, responses...)
+	c.mu.Unlock()
+
+	for _, r := range responses {
+		select {
+		case c.arrived <- r.Operation:
+		default:
+		}
+	}
+}
+
+func (c *apiCapture) has(operation string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, r := range c.responses {
+		if r.Operation == operation {
+			return true
+		}
+	}
+	return false
+}
+
+// payloads returns the parsed JSON bodies captured for operation.
+func (c *apiCapture) payloads(operation string) []interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var out []interface{}
+	for _, r := range c.responses {
+		if r.Operation != operation {
+			continue
+		}
+		var v interface{}
+		if err := json.Unmarshal(r.Body, &v); err == nil {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// searchResult is what one StaysSearch result tells us about a listing
+// without opening its detail page.
+type searchResult struct {
+	RoomID      string
+	Title       string
+	RawPrice    string
+	Rating      float64
+	ReviewCount int
+	Latitude    float64
+	Longitude   float64
+
+	// PriceQualifier says what RawPrice is for: "night", "total" or
+	// "for 5 nights".
+	PriceQualifier string
+
+	// Neighborhood is the area from the card heading ("Condo in Bukit
+	// Bintang"), the only place search results name it.
+	Neighborhood string
+
+	// Thumbnail is the card's first picture.
+	Thumbnail string
+}
+
+const demandListingPrefix = "DemandStayListing:"
+
+var ratingPattern = regexp.MustCompile(`([0-9]+(?:\.[0-9]+)?)\s*\(([0-9,]+)\)`)
+
+// parseSearchResults pulls listing records out of StaysSearch payloads.
+// The same shape is embedded in the search page's own state for the
+// first results page, so both API bodies and state blobs can be passed.
+func parseSearchResults(roots []interface{}) []searchResult {
+	var out []searchResult
+	for _, root := range roots {
+		results, ok := lookupPath(root, "**.staysSearch.results.searchResults")
+		if !ok {
+			continue
+		}
+		list, ok := results.([]interface{})
+		if !ok {
+			continue
+		}
+		for _, item := range list {
+			if r, ok := parseSearchResult(item); ok {
+				out = append(out, r)
+			}
+		}
+	}
+	return out
+}
+
+func parseSearchResult(item interface{}) (searchResult, bool) {
+	str := func(paths ...string) string {
+		for _, p := range paths {
+			if v, ok := lookupPath(item, p); ok {
+				if s := strings.TrimSpace(scalarString(v)); s != "" {
+					return s
+				}
+			}
+		}
+		return ""
+	}
+	num := func(paths ...string) float64 {
+		f, _ := strconv.ParseFloat(str(paths...), 64)
+		return f
+	}
+
+	var r searchResult
+
+	r.RoomID = str("listing.id")
+	if r.RoomID == "" {
+		// demandStayListing.id is base64("DemandStayListing:<room id>")
+		if raw, err := base64.StdEncoding.DecodeString(str("demandStayListing.id")); err == nil {
+			r.RoomID = strings.TrimPrefix(string(raw), demandListingPrefix)
+		}
+	}
+	if r.RoomID == "" {
+		return searchResult{}, false
+	}
+
+	r.Title = str(
+		"nameLocalized.localizedStringWithTranslationPreference",
+		"demandStayListing.description.name.localizedStringWithTranslationPreference",
+		"listing.name",
+		"title",
+	)
+	r.RawPrice = str(
+		"structuredDisplayPrice.primaryLine.discountedPrice",
+		"structuredDisplayPrice.primaryLine.price",
+		"pricingQuote.structuredStayDisplayPrice.primaryLine.discountedPrice",
+		"pricingQuote.structuredStayDisplayPrice.primaryLine.price",
+	)
+	r.PriceQualifier = str(
+		"structuredDisplayPrice.primaryLine.qualifier",
+		"pricingQuote.structuredStayDisplayPrice.primaryLine.qualifier",
+	)
+
+	if m := ratingPattern.FindStringSubmatch(str("avgRatingLocalized", "listing.avgRatingLocalized")); m != nil {
+		r.Rating, _ = strconv.ParseFloat(m[1], 64)
+		r.ReviewCount, _ = strconv.Atoi(strings.ReplaceAll(m[2], ",", ""))
+	}
+
+	r.Thumbnail = str("contextualPictures.picture", "listing.contextualPictures.picture")
+
+	r.Latitude = num("demandStayListing.location.coordinate.latitude", "listing.coordinate.latitude")
+	r.Longitude = num("demandStayListing.location.coordinate.longitude", "listing.coordinate.longitude")
+
+	if _, area, ok := strings.Cut(str("title"), " in "); ok {
+		r.Neighborhood = strings.TrimSpace(area)
+	}
+
+	return r, true
+}
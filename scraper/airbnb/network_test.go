@@ -0,0 +1,87 @@
+package airbnb
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// staysSearchBody is a StaysSearch response trimmed to the fields the
+// scraper reads, plus one result without any ID.
+const staysSearchBody = `{"data": {"presentation": {"staysSearch": {"results": {
+	"searchResults": [
+		{
+			"__typename": "StaySearchResult",
+			"listing": {
+				"id": "41001101",
+				"name": "Skyline studio",
+				"avgRatingLocalized": "4.92 (1,204)",
+				"coordinate": {"latitude": 3.1466, "longitude": 101.7108},
+				"contextualPictures": [{"picture": "https://a0.muscache.com/im/pictures/a.jpg?im_w=720"}]
+			},
+			"title": "Condo in Bukit Bintang",
+			"structuredDisplayPrice": {"primaryLine": {"price": "$150", "discountedPrice": "$120", "qualifier": "night"}}
+		},
+		{
+			"__typename": "StaySearchResult",
+			"listing": {"id": "52345", "avgRatingLocalized": "New"},
+			"title": "Loft",
+			"structuredDisplayPrice": {"primaryLine": {"price": "$600", "qualifier": "for 5 nights"}}
+		},
+		{"__typename": "StaySearchResult", "title": "Ad slot"}
+	]
+}}}}}`
+
+// searchStateBlob is the server-rendered page state of a first results
+// page, where the results are wrapped in niobe cache entries and the room
+// ID only appears base64 encoded.
+const searchStateBlob = `{"niobeMinimalClientData": [[
+	"StaysSearch:{\"query\":\"Kuala Lumpur\"}",
+	{"data": {"presentation": {"staysSearch": {"results": {"searchResults": [
+		{
+			"demandStayListing": {
+				"id": "RGVtYW5kU3RheUxpc3Rpbmc6NTIzNDU=",
+				"description": {"name": {"localizedStringWithTranslationPreference": "Garden loft"}},
+				"location": {"coordinate": {"latitude": "3.1390", "longitude": "101.6869"}}
+			},
+			"avgRatingLocalized": "4.8 (25)",
+			"title": "Apartment in Chow Kit",
+			"contextualPictures": [{"picture": "/im/pictures/b.jpg"}],
+			"pricingQuote": {"structuredStayDisplayPrice": {"primaryLine": {"price": "RM 1,250", "qualifier": "total"}}}
+		}
+	]}}}}}
+]]}`
+
+func TestParseSearchResults(t *testing.T) {
+	var api interface{}
+	if err := json.Unmarshal([]byte(staysSearchBody), &api); err != nil {
+		t.Fatal(err)
+	}
+	state := parseBlobs([]string{searchStateBlob, `{"unrelated": true}`})
+	if len(state) != 2 {
+		t.Fatalf("parseBlobs returned %d roots, want 2", len(state))
+	}
+
+	got := parseSearchResults(append(state, api))
+	want := []searchResult{
+		{
+			RoomID: "52345", Title: "Garden loft", RawPrice: "RM 1,250", PriceQualifier: "total",
+			Rating: 4.8, ReviewCount: 25, Latitude: 3.139, Longitude: 101.6869,
+			Neighborhood: "Chow Kit", Thumbnail: "/im/pictures/b.jpg",
+		},
+		{
+			RoomID: "41001101", Title: "Skyline studio", RawPrice: "$120", PriceQualifier: "night",
+			Rating: 4.92, ReviewCount: 1204, Latitude: 3.1466, Longitude: 101.7108,
+			Neighborhood: "Bukit Bintang", Thumbnail: "https://a0.muscache.com/im/pictures/a.jpg?im_w=720",
+		},
+		{RoomID: "52345", Title: "Loft", RawPrice: "$600", PriceQualifier: "for 5 nights"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseSearchResults returned %d results, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("result %d:\n got %+v\nwant %+v", i, got[i], want[i])
+		}
+	}
+}
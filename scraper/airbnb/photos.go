@@ -0,0 +1,352 @@
+package airbnb
+
+import (
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// placeholderCaption matches the alt text Airbnb gives photos without a
+// caption ("Listing image 3").
+var placeholderCaption = regexp.MustCompile(`(?i)^listing image\s*[0-9]*$`)
+
+// untaggedRoom is the photo tour heading of photos without a room tag.
+const untaggedRoom = "additional photos"
+
+// parsePhotos reads a photo list in any of the shapes the default
+// selector set produces:
+//
+//   - the photo tour section from the API or page state, whose
+//     "mediaItems" are [{"id", "baseUrl", "caption"}] and whose
+//     "roomTourItems" are [{"title": "Bedroom 1", "photoIds": [...]}]
+//   - JSON-LD image, a list of URLs or ImageObjects
+//   - the photo tour script's [{"url", "caption", "room"}]
+//   - plain text, one URL per line
+//
+// URLs lose their query (Airbnb's resizing parameters) and photos are
+// deduplicated by URL, keeping the first.
+func parsePhotos(raw string) []models.Photo {
+	var v interface{}
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		var out []models.Photo
+		for _, line := range strings.Split(raw, "\n") {
+			out = append(out, models.Photo{URL: line})
+		}
+		return dedupePhotos(out)
+	}
+
+	var out []models.Photo
+	rooms := make(map[string]string) // photo ID -> room
+	ids := make(map[int]string)      // position in out -> photo ID
+	collectPhotos(v, rooms, ids, &out)
+	for i, id := range ids {
+		if room := rooms[id]; room != "" && out[i].Room == "" {
+			out[i].Room = room
+		}
+	}
+	return dedupePhotos(out)
+}
+
+func collectPhotos(v interface{}, rooms map[string]string, ids map[int]string, out *[]models.Photo) {
+	switch t := v.(type) {
+	case []interface{}:
+		for _, item := range t {
+			collectPhotos(item, rooms, ids, out)
+		}
+
+	case map[string]interface{}:
+		// A photo tour room lists the IDs of its photos.
+		if photoIDs, ok := t["photoIds"].([]interface{}); ok {
+			title := firstString(t, "title", "name")
+			for _, id := range photoIDs {
+				rooms[scalarString(id)] = title
+			}
+			return
+		}
+
+		u := firstString(t, "baseUrl", "url", "contentUrl", "src")
+		if u == "" {
+			// Only lists and objects hold photos; other strings are
+			// names and type tags.
+			for _, k := range sortedKeys(t) {
+				if _, ok := t[k].(string); !ok {
+					collectPhotos(t[k], rooms, ids, out)
+				}
+			}
+			return
+		}
+		p := models.Photo{
+			URL:     u,
+			Caption: firstString(t, "caption", "accessibilityLabel", "name"),
+			Room:    firstString(t, "room"),
+		}
+		if meta, ok := t["imageMetadata"].(map[string]interface{}); ok && p.Caption == "" {
+			p.Caption = firstString(meta, "caption")
+		}
+		if id := scalarString(t["id"]); id != "" {
+			ids[len(*out)] = id
+		}
+		*out = append(*out, p)
+
+	case string:
+		*out = append(*out, models.Photo{URL: t})
+	}
+}
+
+// sortedKeys keeps the photo order of objects stable.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func dedupePhotos(in []models.Photo) []models.Photo {
+	seen := make(map[string]bool)
+	var out []models.Photo
+	for _, p := range in {
+		p.URL = photoURL(p.URL)
+		if p.URL == "" || seen[p.URL] {
+			continue
+		}
+		seen[p.URL] = true
+		p.Caption = strings.Join(strings.Fields(p.Caption), " ")
+		if placeholderCaption.MatchString(p.Caption) {
+			p.Caption = ""
+		}
+		p.Room = strings.Join(strings.Fields(p.Room), " ")
+		if strings.EqualFold(p.Room, untaggedRoom) {
+			p.Room = ""
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// photoURL strips the query and fragment from a photo URL; root-relative
+// URLs are kept for resolvePhotos. Anything else (data: URLs, stray text)
+// gives "".
+func photoURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Path == "" {
+		return ""
+	}
+	if u.Scheme != "http" && u.Scheme != "https" && !strings.HasPrefix(u.Path, "/") {
+		return ""
+	}
+	u.RawQuery, u.Fragment = "", ""
+	return u.String()
+}
+
+// resolvePhotos makes relative photo URLs absolute against pageURL.
+func resolvePhotos(photos []models.Photo, pageURL string) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return
+	}
+	for i := range photos {
+		if u, err := url.Parse(photos[i].URL); err == nil && !u.IsAbs() {
+			photos[i].URL = base.ResolveReference(u).String()
+		}
+	}
+}
+
+// maxPhotoBytes caps a single download.
+const maxPhotoBytes = 32 << 20
+
+// photoExtensions names stored files by their content type.
+var photoExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/avif": ".avif",
+	"image/gif":  ".gif",
+}
+
+// photoStore is a content-addressed directory of downloaded photos:
+//
+//	ab/ab12...ef.jpg   the image, named by its SHA-256
+//	index.json         photo URL → file, so known URLs are not fetched again
+type photoStore struct {
+	dir    string
+	client *http.Client
+
+	mu    sync.Mutex
+	index map[string]string
+}
+
+func newPhotoStore(dir string, timeout time.Duration) (*photoStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create photos dir: %w", err)
+	}
+	p := &photoStore{
+		dir:    dir,
+		client: &http.Client{Timeout: timeout},
+		index:  make(map[string]string),
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, "index.json")); err == nil {
+		json.Unmarshal(b, &p.index)
+	}
+	return p, nil
+}
+
+// lookup returns the stored file for a photo URL, if it was downloaded
+// before and the file is still there.
+func (p *photoStore) lookup(photoURL string) (string, bool) {
+	p.mu.Lock()
+	file, ok := p.index[photoURL]
+	p.mu.Unlock()
+	if !ok {
+		return "", false
+	}
+	if _, err := os.Stat(filepath.Join(p.dir, filepath.FromSlash(file))); err != nil {
+		return "", false
+	}
+	return file, true
+}
+
+// fetch downloads photoURL into the store unless it is already there and
+// returns its file, relative to the store.
+func (p *photoStore) fetch(ctx context.Context, photoURL string) (string, error) {
+	if file, ok := p.lookup(photoURL); ok {
+		return file, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, photoURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", utils.RandomUserAgent())
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPhotoBytes+1))
+	if err != nil {
+		return "", err
+	}
+	if len(body) > maxPhotoBytes {
+		return "", fmt.Errorf("larger than %d MB", maxPhotoBytes>>20)
+	}
+
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:])
+	ext, ok := photoExtensions[strings.TrimSpace(strings.Split(resp.Header.Get("Content-Type"), ";")[0])]
+	if !ok {
+		ext = strings.ToLower(path.Ext(req.URL.Path))
+	}
+	file := hash[:2] + "/" + hash + ext
+
+	// The write is skipped when the image is stored under another URL.
+	dst := filepath.Join(p.dir, filepath.FromSlash(file))
+	if _, err := os.Stat(dst); err != nil {
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return "", err
+		}
+		if err := writeFileAtomic(dst, body); err != nil {
+			return "", err
+		}
+	}
+
+	p.mu.Lock()
+	p.index[photoURL] = file
+	p.mu.Unlock()
+	return file, nil
+}
+
+// writeFileAtomic writes data to dst through a temp file of its own in
+// the same directory, so workers storing the same image at once never
+// write into each other's file and dst is never seen half written.
+func writeFileAtomic(dst string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(dst), filepath.Base(dst)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+// save writes the URL index.
+func (p *photoStore) save() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	b, err := json.MarshalIndent(p.index, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(p.dir, "index.json.tmp")
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(p.dir, "index.json"))
+}
+
+// photoHash is the SHA-256 a store file is named by.
+func photoHash(file string) string {
+	name := path.Base(file)
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// downloadPhotos puts the listing's photos into the photo store and
+// fills their Hash and File. A photo that fails to download keeps its
+// URL only; the failures are summed up in one warning.
+func (s *Scraper) downloadPhotos(ctx context.Context, l *models.Listing) {
+	if s.photos == nil || len(l.Photos) == 0 {
+		return
+	}
+
+	failed := 0
+	var lastErr error
+	for i := range l.Photos {
+		if ctx.Err() != nil {
+			return
+		}
+		file, err := s.photos.fetch(ctx, l.Photos[i].URL)
+		if err != nil {
+			failed++
+			lastErr = err
+			continue
+		}
+		l.Photos[i].File = file
+		l.Photos[i].Hash = photoHash(file)
+	}
+	if failed > 0 {
+		utils.Warn("%d of %d photos of %s not downloaded: %v", failed, len(l.Photos), l.URL, lastErr)
+	}
+	if err := s.photos.save(); err != nil {
+		utils.Warn("Photo index not saved: %v", err)
+	}
+}
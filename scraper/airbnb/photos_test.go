@@ -0,0 +1,64 @@
+package airbnb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestPhotoStoreConcurrentFetch has several workers store the same image
+// under different URLs at once: each must end up with the full file and
+// no temp file may be left behind.
+func TestPhotoStoreConcurrentFetch(t *testing.T) {
+	image := bytes.Repeat([]byte("jpeg"), 64<<10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(image)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	p, err := newPhotoStore(dir, 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const workers = 8
+	files := make([]string, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			files[i], errs[i] = p.fetch(context.Background(), fmt.Sprintf("%s/photo-%d.jpg", srv.URL, i))
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range files {
+		if errs[i] != nil {
+			t.Fatalf("fetch %d: %v", i, errs[i])
+		}
+		if files[i] != files[0] {
+			t.Errorf("fetch %d stored %s, want %s", i, files[i], files[0])
+		}
+	}
+	got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(files[0])))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, image) {
+		t.Errorf("stored image has %d bytes, want %d", len(got), len(image))
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(dir, "*", "*.tmp")); len(tmp) > 0 {
+		t.Errorf("temp files left behind: %v", tmp)
+	}
+}
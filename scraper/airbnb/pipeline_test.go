@@ -0,0 +1,176 @@
+package airbnb_test
+
+import (
+	"airbnb-scraper/config"
+	"airbnb-scraper/mockairbnb"
+	"airbnb-scraper/models"
+	"airbnb-scraper/scraper/airbnb"
+	"airbnb-scraper/services"
+	"airbnb-scraper/storage"
+	"context"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// requireChrome skips the test when no Chrome can be started here.
+func requireChrome(t *testing.T) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("end-to-end run skipped in -short mode")
+	}
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), append(chromedp.DefaultExecAllocatorOptions[:], chromedp.Headless)...)
+	defer cancelAlloc()
+	ctx, cancel := chromedp.NewContext(allocCtx)
+	defer cancel()
+	ctx, cancelTimeout := context.WithTimeout(ctx, 30*time.Second)
+	defer cancelTimeout()
+	if err := chromedp.Run(ctx); err != nil {
+		t.Skipf("Chrome not available: %v", err)
+	}
+}
+
+// mockConfig points a fast config at srv: no delays, every card of every
+// results page, and only the stages Server.Listings covers.
+func mockConfig(t *testing.T, srv *mockairbnb.Server) *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.BaseURL = srv.URL + "/"
+	cfg.MaxPages = 2
+	cfg.MaxWorkers = 4
+	cfg.CardsPerPage = 0
+	cfg.MaxSectionPages = 2
+	cfg.RequestTimeout = 30 * time.Second
+	cfg.MinDelay, cfg.MaxDelay = 0, 0
+	cfg.MaxReviews = 0
+	cfg.CalendarMonths = 0
+	cfg.HostProfiles = true
+	cfg.CheckpointDir = t.TempDir()
+	cfg.CSVPath = filepath.Join(t.TempDir(), "listings.csv")
+	return cfg
+}
+
+// runPool runs a whole scrape of the mock site and returns the cleaned
+// listings.
+func runPool(t *testing.T, cfg *config.Config) []models.Listing {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	s, err := airbnb.NewScraper(ctx, cfg)
+	if err != nil {
+		t.Fatalf("NewScraper: %v", err)
+	}
+	defer s.Close()
+
+	checkpoint, err := airbnb.NewCheckpoint(cfg.CheckpointDir, airbnb.NewRunID())
+	if err != nil {
+		t.Fatalf("NewCheckpoint: %v", err)
+	}
+	return services.CleanListings(airbnb.NewWorkerPool(s, cfg, checkpoint).Run(ctx))
+}
+
+func sortByID(listings []models.Listing) {
+	sort.Slice(listings, func(i, j int) bool { return listings[i].ID < listings[j].ID })
+}
+
+// normalize makes empty lists nil, so a list the scraper found empty
+// equals one the mock never set.
+func normalize(l *models.Listing) {
+	if len(l.Amenities) == 0 {
+		l.Amenities = nil
+	}
+	if len(l.Photos) == 0 {
+		l.Photos = nil
+	}
+	if len(l.Reviews) == 0 {
+		l.Reviews = nil
+	}
+	if len(l.Calendar) == 0 {
+		l.Calendar = nil
+	}
+}
+
+// compareListings reports every listing of got that differs from want.
+func compareListings(t *testing.T, got, want []models.Listing) {
+	t.Helper()
+	sortByID(got)
+	sortByID(want)
+	if len(got) != len(want) {
+		t.Fatalf("got %d listings, want %d", len(got), len(want))
+	}
+	for i := range got {
+		got[i].Sources = nil
+		normalize(&got[i])
+		normalize(&want[i])
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("listing %d:\n got %+v\nwant %+v", want[i].ID, got[i], want[i])
+		}
+	}
+}
+
+// TestPipeline runs WorkerPool.Run → CleanListings → CSV → report against
+// the mock site, with rate limiting and forbidden rooms, and checks the
+// result against Server.Listings.
+func TestPipeline(t *testing.T) {
+	requireChrome(t)
+
+	srv := mockairbnb.Start(mockairbnb.Options{RateLimitFirst: 1, ForbiddenEvery: 5})
+	defer srv.Close()
+	cfg := mockConfig(t, srv)
+
+	listings := runPool(t, cfg)
+	compareListings(t, listings, srv.Listings())
+
+	if err := storage.NewCSVWriter(cfg.CSVPath).Write(listings); err != nil {
+		t.Fatalf("CSV write: %v", err)
+	}
+	read, err := storage.ReadCSV(cfg.CSVPath)
+	if err != nil {
+		t.Fatalf("CSV read: %v", err)
+	}
+	if len(read) != len(listings) {
+		t.Fatalf("CSV has %d listings, want %d", len(read), len(listings))
+	}
+
+	report := services.GenerateReport(read)
+	if report.TotalListings != len(listings) {
+		t.Errorf("report counts %d listings, want %d", report.TotalListings, len(listings))
+	}
+	if report.AveragePrice <= 0 || report.MinPrice > report.MaxPrice {
+		t.Errorf("report prices: average %.2f, min %.2f, max %.2f", report.AveragePrice, report.MinPrice, report.MaxPrice)
+	}
+}
+
+// TestPipelineMissingFields checks that fields missing from every detail
+// page come out empty rather than failing the listing.
+func TestPipelineMissingFields(t *testing.T) {
+	requireChrome(t)
+
+	srv := mockairbnb.Start(mockairbnb.Options{MissingFields: []string{"rating", "capacity", "amenities", "photos"}})
+	defer srv.Close()
+
+	compareListings(t, runPool(t, mockConfig(t, srv)), srv.Listings())
+}
+
+// TestPipelineCardsOnly checks cards-only runs against
+// Server.CardListings, and that no detail page is opened.
+func TestPipelineCardsOnly(t *testing.T) {
+	requireChrome(t)
+
+	srv := mockairbnb.Start(mockairbnb.Options{})
+	defer srv.Close()
+	cfg := mockConfig(t, srv)
+	cfg.CardsOnly = true
+
+	want := srv.CardListings()
+	compareListings(t, runPool(t, cfg), want)
+	for _, l := range want {
+		if path := "/rooms/" + l.Key(); srv.Hits(path) > 0 {
+			t.Errorf("%s was opened in cards-only mode", path)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package airbnb
+
+import (
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"encoding/json"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// moneyPattern matches one amount of a price line: "$1,234.50",
+	// "-$20", "RM 95", "€80".
+	moneyPattern = regexp.MustCompile(`([-−–]\s*)?(?:[$€£]|RM)\s*([0-9][0-9,]*(?:\.[0-9]+)?)`)
+
+	stayPattern  = regexp.MustCompile(`(?i)[x×]\s*([0-9]+)\s+nights?`)
+	nightsLabel  = regexp.MustCompile(`(?i)\bfor\s+([0-9]+)\s+nights?`)
+	datePattern  = regexp.MustCompile(`\b([0-9]{4}-[0-9]{2}-[0-9]{2}|[0-9]{1,2}/[0-9]{1,2}/[0-9]{4})\b`)
+	dateLayouts  = []string{config.DateLayout, "1/2/2006"}
+	priceLabels  = []string{"description", "label", "title"}
+	priceAmounts = []string{"priceString", "price", "amount"}
+)
+
+// moneyValue is the amount of a moneyPattern match, negative when it has
+// a minus sign.
+func moneyValue(m []string) float64 {
+	v, _ := strconv.ParseFloat(strings.ReplaceAll(m[2], ",", ""), 64)
+	if m[1] != "" {
+		return -v
+	}
+	return v
+}
+
+// setPriceUnit records what the headline price is for, from its
+// qualifier ("night", "total") or label ("$122 for 2 nights"). A label
+// quoting a different amount than Price is about another figure and is
+// ignored.
+func setPriceUnit(l *models.Listing, raw string) {
+	if m := moneyPattern.FindStringSubmatch(raw); m != nil && moneyValue(m) != l.Price {
+		return
+	}
+	if l.Price <= 0 {
+		return
+	}
+
+	lower := strings.ToLower(raw)
+	if m := nightsLabel.FindStringSubmatch(lower); m != nil {
+		l.Nights, _ = strconv.Atoi(m[1])
+		l.TotalPrice = l.Price
+		return
+	}
+	switch {
+	case strings.Contains(lower, "total"):
+		l.TotalPrice = l.Price
+	case strings.Contains(lower, "night"):
+		l.PricePerNight = l.Price
+	}
+}
+
+// setBreakdown reads the booking panel's price details, either the API's
+// [{"items": [{"description": "$45 x 2 nights", "priceString": "$90"}]}]
+// or the panel's text with one "label amount" per line (the amount may
+// be on the line after its label). A "Total" that includes a taxes line
+// is stored before taxes.
+func setBreakdown(l *models.Listing, raw string) {
+	var lines []string
+	var v interface{}
+	if err := json.Unmarshal([]byte(raw), &v); err == nil {
+		collectPriceLines(v, &lines)
+	} else {
+		lines = strings.Split(raw, "\n")
+	}
+
+	var total float64
+	var withTaxes bool
+	label := ""
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			continue
+		}
+		matches := moneyPattern.FindAllStringSubmatchIndex(line, -1)
+		if len(matches) == 0 {
+			label = line
+			continue
+		}
+		last := matches[len(matches)-1]
+		if strings.TrimSpace(line[last[1]:]) != "" {
+			// "$45 x 2 nights": an amount inside a label.
+			label = line
+			continue
+		}
+		text := strings.TrimSpace(line[:last[0]])
+		if text == "" {
+			text = label
+		}
+		label = ""
+
+		amount := moneyValue(subMatches(line, last))
+		lower := strings.ToLower(text)
+		switch {
+		case stayPattern.MatchString(lower):
+			l.Nights, _ = strconv.Atoi(stayPattern.FindStringSubmatch(lower)[1])
+			if m := moneyPattern.FindStringSubmatch(text); m != nil {
+				l.NightlyRate = moneyValue(m)
+			} else if l.Nights > 0 {
+				l.NightlyRate = amount / float64(l.Nights)
+			}
+		case strings.HasPrefix(lower, "total"):
+			total = amount
+			withTaxes = !strings.Contains(lower, "before tax")
+		case strings.Contains(lower, "cleaning"):
+			l.CleaningFee = amount
+		case strings.Contains(lower, "service fee"):
+			l.ServiceFee = amount
+		case strings.Contains(lower, "discount") || amount < 0:
+			l.Discount += math.Abs(amount)
+		case strings.Contains(lower, "tax"):
+			l.Taxes += amount
+		}
+	}
+
+	if total > 0 {
+		if withTaxes {
+			total -= l.Taxes
+		}
+		l.TotalPrice = total
+	}
+}
+
+func subMatches(s string, loc []int) []string {
+	m := make([]string, len(loc)/2)
+	for i := range m {
+		if loc[2*i] >= 0 {
+			m[i] = s[loc[2*i]:loc[2*i+1]]
+		}
+	}
+	return m
+}
+
+// collectPriceLines flattens price detail items into "label amount"
+// lines. Keys are walked in sorted order so the result is stable.
+func collectPriceLines(v interface{}, lines *[]string) {
+	switch t := v.(type) {
+	case []interface{}:
+		for _, item := range t {
+			collectPriceLines(item, lines)
+		}
+
+	case map[string]interface{}:
+		label, amount := firstString(t, priceLabels...), firstString(t, priceAmounts...)
+		if label != "" && moneyPattern.MatchString(amount) {
+			*lines = append(*lines, label+" "+amount)
+			return
+		}
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			collectPriceLines(t[k], lines)
+		}
+	}
+}
+
+// parseStayDates reads the check-in and check-out dates from text
+// holding both ("2026-11-02 2026-11-04", "11/2/2026 – 11/4/2026") and
+// returns them as YYYY-MM-DD, or empty strings.
+func parseStayDates(raw string) (string, string) {
+	var dates []time.Time
+	for _, s := range datePattern.FindAllString(raw, -1) {
+		if t, ok := parseDate(s); ok {
+			dates = append(dates, t)
+		}
+	}
+	if len(dates) < 2 || !dates[1].After(dates[0]) {
+		return "", ""
+	}
+	return dates[0].Format(config.DateLayout), dates[1].Format(config.DateLayout)
+}
+
+func parseDate(s string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// fillPricing completes the price fields once every chain has run: the
+// number of nights from the stay dates when no price line gave it, and
+// the normalized PricePerNight.
+func fillPricing(l *models.Listing) {
+	if l.Nights == 0 && l.CheckIn != "" {
+		in, okIn := parseDate(l.CheckIn)
+		out, okOut := parseDate(l.CheckOut)
+		if okIn && okOut {
+			l.Nights = int(out.Sub(in).Hours() / 24)
+		}
+	}
+
+	switch {
+	case l.TotalPrice > 0 && l.Nights > 0:
+		l.PricePerNight = math.Round(l.TotalPrice/float64(l.Nights)*100) / 100
+	case l.NightlyRate > 0:
+		l.PricePerNight = l.NightlyRate
+	}
+}
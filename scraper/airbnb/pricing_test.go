@@ -0,0 +1,114 @@
+package airbnb
+
+import (
+	"airbnb-scraper/models"
+	"reflect"
+	"testing"
+)
+
+func TestSetBreakdown(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want models.Listing
+	}{
+		{
+			name: "API price details",
+			raw: `[{"items": [
+				{"description": "$45 x 2 nights", "priceString": "$90"},
+				{"description": "Cleaning fee", "priceString": "$20"},
+				{"description": "Airbnb service fee", "priceString": "$15"},
+				{"description": "Weekly stay discount", "priceString": "-$5"},
+				{"description": "Taxes", "priceString": "$10"},
+				{"description": "Total", "priceString": "$130"}
+			]}]`,
+			want: models.Listing{Nights: 2, NightlyRate: 45, CleaningFee: 20, ServiceFee: 15, Discount: 5, Taxes: 10, TotalPrice: 120},
+		},
+		{
+			name: "panel text with amounts on the next line",
+			raw:  "$45 x 2 nights\n$90\nCleaning fee\n$20\nTotal before taxes\n$110",
+			want: models.Listing{Nights: 2, NightlyRate: 45, CleaningFee: 20, TotalPrice: 110},
+		},
+		{
+			name: "panel text on one line each",
+			raw:  "RM 95 x 3 nights RM 285\nService fee RM 40\nTaxes RM 25\nTotal (MYR) RM 350",
+			want: models.Listing{Nights: 3, NightlyRate: 95, ServiceFee: 40, Taxes: 25, TotalPrice: 325},
+		},
+		{
+			name: "nightly rate from the stay amount",
+			raw:  "Stay × 3 nights $300\nTotal before taxes $300",
+			want: models.Listing{Nights: 3, NightlyRate: 100, TotalPrice: 300},
+		},
+		{
+			name: "unlabelled negative amount is a discount",
+			raw:  "Early bird −$12.50\nTotal before taxes $87.50",
+			want: models.Listing{Discount: 12.5, TotalPrice: 87.5},
+		},
+		{
+			name: "no total",
+			raw:  "Cleaning fee $20",
+			want: models.Listing{CleaningFee: 20},
+		},
+		{name: "empty", raw: "", want: models.Listing{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got models.Listing
+			setBreakdown(&got, tt.raw)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("setBreakdown:\n got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFillPricing(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         models.Listing
+		wantNights int
+		wantPPN    float64
+	}{
+		{"total over nights", models.Listing{TotalPrice: 120, Nights: 2, NightlyRate: 45}, 2, 60},
+		{"nights from stay dates", models.Listing{TotalPrice: 100, CheckIn: "2026-11-02", CheckOut: "2026-11-05"}, 3, 33.33},
+		{"US dates", models.Listing{TotalPrice: 90, CheckIn: "11/2/2026", CheckOut: "11/4/2026"}, 2, 45},
+		{"nightly rate without total", models.Listing{NightlyRate: 45}, 0, 45},
+		{"total without nights", models.Listing{TotalPrice: 300, NightlyRate: 80}, 0, 80},
+		{"headline nightly price kept", models.Listing{Price: 70, PricePerNight: 70}, 0, 70},
+		{"bad dates", models.Listing{TotalPrice: 100, CheckIn: "soon", CheckOut: "later"}, 0, 0},
+		{"nothing to go on", models.Listing{}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := tt.in
+			fillPricing(&l)
+			if l.Nights != tt.wantNights || l.PricePerNight != tt.wantPPN {
+				t.Errorf("fillPricing: nights %d, price per night %v; want %d, %v", l.Nights, l.PricePerNight, tt.wantNights, tt.wantPPN)
+			}
+		})
+	}
+}
+
+func TestSetPriceUnit(t *testing.T) {
+	tests := []struct {
+		raw        string
+		wantTotal  float64
+		wantPPN    float64
+		wantNights int
+	}{
+		{"night", 0, 120, 0},
+		{"$120 night", 0, 120, 0},
+		{"total", 120, 0, 0},
+		{"$120 for 2 nights", 120, 0, 2},
+		{"$90 night", 0, 0, 0}, // about another amount
+		{"", 0, 0, 0},
+	}
+	for _, tt := range tests {
+		l := models.Listing{Price: 120}
+		setPriceUnit(&l, tt.raw)
+		if l.TotalPrice != tt.wantTotal || l.PricePerNight != tt.wantPPN || l.Nights != tt.wantNights {
+			t.Errorf("setPriceUnit(%q): total %v, per night %v, nights %d; want %v, %v, %d",
+				tt.raw, l.TotalPrice, l.PricePerNight, l.Nights, tt.wantTotal, tt.wantPPN, tt.wantNights)
+		}
+	}
+}
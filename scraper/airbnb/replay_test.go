@@ -0,0 +1,80 @@
+package airbnb_test
+
+import (
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"airbnb-scraper/scraper/airbnb"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files from the replayed listings")
+
+// TestReplayMockFixture is a record/replay round trip, not a regression
+// test against live markup: testdata/fixtures was recorded from the mock
+// site (package mockairbnb), then replayed under the live base URL and
+// compared with its golden file. Run with -update after re-recording.
+func TestReplayMockFixture(t *testing.T) {
+	requireChrome(t)
+
+	const (
+		roomURL = "https://www.airbnb.com/rooms/41001101"
+		golden  = "testdata/room-41001101.golden.json"
+	)
+
+	cfg := config.DefaultConfig()
+	cfg.BaseURL = "https://www.airbnb.com/"
+	cfg.FixturesMode = config.FixturesReplay
+	cfg.FixturesDir = "testdata/fixtures"
+	cfg.RequestTimeout = 30 * time.Second
+	cfg.MinDelay, cfg.MaxDelay = 0, 0
+	cfg.MaxReviews = 0
+	cfg.CalendarMonths = 0
+	cfg.HostProfiles = true
+	cfg.PhotosDir = ""
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	s, err := airbnb.NewScraper(ctx, cfg)
+	if err != nil {
+		t.Fatalf("NewScraper: %v", err)
+	}
+	defer s.Close()
+
+	got, err := s.ScrapePropertyPage(ctx, roomURL)
+	if err != nil {
+		t.Fatalf("ScrapePropertyPage: %v", err)
+	}
+	got.Sources = nil
+	normalize(&got)
+
+	if *update {
+		b, err := json.MarshalIndent(got, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, append(b, '\n'), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	b, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want models.Listing
+	if err := json.Unmarshal(b, &want); err != nil {
+		t.Fatal(err)
+	}
+	normalize(&want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("replayed listing differs from %s:\n got %+v\nwant %+v", golden, got, want)
+	}
+}
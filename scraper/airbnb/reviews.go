@@ -0,0 +1,333 @@
+package airbnb
+
+import (
+	"airbnb-scraper/config"
+	"airbnb-scraper/models"
+	"context"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// reviewStalls is how many scrolls in a row may load no new reviews
+// before the modal counts as exhausted.
+const reviewStalls = 3
+
+// reviewsDialogJS finds the open reviews modal.
+const reviewsDialogJS = `Array.from(document.querySelectorAll('[role="dialog"]'))
+	.find(d => !d.hidden && d.querySelector('[data-review-id]'))`
+
+// scrapeReviews opens the detail page's reviews modal and scrolls it
+// until cfg.MaxReviews reviews are loaded or no more arrive, then reads
+// them from the captured StaysPdpReviewsQuery responses, or from the
+// modal itself when none were captured. It gets its own RequestTimeout.
+func (s *Scraper) scrapeReviews(tabCtx context.Context, capture *apiCapture) ([]models.Review, error) {
+	ctx, cancel := context.WithTimeout(tabCtx, s.cfg.RequestTimeout)
+	defer cancel()
+
+	var opened bool
+	err := chromedp.Run(ctx, chromedp.Evaluate(`(() => {
+		const button = Array.from(document.querySelectorAll('button, a')).find(el =>
+			/show all\s+([0-9,]+\s+)?reviews/i.test(el.textContent || '')
+		);
+		if (!button) return false;
+		button.click();
+		return true;
+	})()`, &opened))
+	if err != nil {
+		return nil, err
+	}
+
+	// In replay there is no modal to open, but the recorded API responses
+	// still hold the reviews.
+	for loaded, stalls := 0, 0; opened && loaded < s.cfg.MaxReviews && stalls < reviewStalls; {
+		var n int
+		err := chromedp.Run(ctx,
+			chromedp.Sleep(time.Second),
+			chromedp.Evaluate(`(() => {
+				const dialog = `+reviewsDialogJS+`;
+				if (!dialog) return 0;
+				const reviews = Array.from(dialog.querySelectorAll('[data-review-id]')).filter(r => r.getClientRects().length > 0);
+				if (!reviews.length) return 0;
+				const panel = dialog.querySelector('[data-testid="pdp-reviews-modal-scrollable-panel"]') ||
+					Array.from(dialog.querySelectorAll('div')).find(d => d.scrollHeight > d.clientHeight + 10) || dialog;
+				panel.scrollTop = panel.scrollHeight;
+				reviews[reviews.length - 1].scrollIntoView();
+				return reviews.length;
+			})()`, &n),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if n > loaded {
+			loaded, stalls = n, 0
+		} else {
+			stalls++
+		}
+	}
+
+	var reviews []models.Review
+	now := time.Now()
+	for _, payload := range capture.payloads(opStaysPdpReviews) {
+		reviews = append(reviews, parseReviews(valueString(payload), now)...)
+	}
+	if len(reviews) == 0 && opened {
+		var raw string
+		err := chromedp.Run(ctx, chromedp.Evaluate(`(() => {
+			const dialog = `+reviewsDialogJS+`;
+			if (!dialog) return '';
+			const dated = /\b(?:January|February|March|April|May|June|July|August|September|October|November|December)\s+[0-9]{4}\b|\b(?:[0-9]+|a|an)\s+(?:day|week|month|year)s?\s+ago\b|\b(?:today|yesterday)\b/i;
+			const lines = text => text.split('\n').map(l => l.trim()).filter(Boolean);
+			const reviews = Array.from(dialog.querySelectorAll('[data-review-id]'))
+				.filter(el => el.getClientRects().length > 0)
+				.map(el => {
+					const all = lines(el.innerText || '');
+					const split = all.findIndex(l => /^response from\b/i.test(l));
+					const own = split < 0 ? all : all.slice(0, split);
+					const heading = el.querySelector('h2, h3');
+					const name = heading ? heading.innerText.trim() : (own[0] || '');
+					const date = (own.find(l => dated.test(l)) || '').match(dated);
+					const translated = own.map(l => l.match(/^translated from\s+(.+)$/i)).find(Boolean);
+					const lang = el.querySelector('[lang]');
+					const stars = el.querySelector('[aria-label*="star" i]');
+					const body = own.filter(l => l !== name && !dated.test(l) && !/^translated from\b/i.test(l))
+						.sort((a, b) => b.length - a.length)[0] || '';
+					return {
+						id: el.getAttribute('data-review-id'),
+						name,
+						date: date ? date[0] : '',
+						language: translated ? translated[1] : (lang ? lang.getAttribute('lang') : ''),
+						rating: stars ? (stars.getAttribute('aria-label').match(/([0-5])/) || [])[1] || '' : '',
+						text: body,
+						response: split < 0 ? '' : all.slice(split + 1).filter(l => !dated.test(l)).join('\n'),
+					};
+				})
+				.filter(r => r.text);
+			return reviews.length ? JSON.stringify(reviews) : '';
+		})()`, &raw))
+		if err != nil {
+			return nil, err
+		}
+		reviews = parseReviews(raw, now)
+	}
+
+	reviews = dedupeReviews(reviews)
+	if len(reviews) > s.cfg.MaxReviews {
+		reviews = reviews[:s.cfg.MaxReviews]
+	}
+	return reviews, nil
+}
+
+// parseReviews reads reviews from a StaysPdpReviewsQuery response
+// (objects with "comments", "createdAt", "reviewer": {"firstName"}) or
+// the modal script's [{"id", "name", "date", "language", "rating",
+// "text", "response"}]. Relative dates count back from now.
+func parseReviews(raw string, now time.Time) []models.Review {
+	var v interface{}
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		return nil
+	}
+	var out []models.Review
+	collectReviews(v, now, &out)
+	return out
+}
+
+func collectReviews(v interface{}, now time.Time, out *[]models.Review) {
+	switch t := v.(type) {
+	case []interface{}:
+		for _, item := range t {
+			collectReviews(item, now, out)
+		}
+
+	case map[string]interface{}:
+		// API reviews have "comments"; the script's have "name" and "text".
+		text := firstString(t, "comments")
+		if _, scripted := t["name"]; text == "" && scripted {
+			text = firstString(t, "text")
+		}
+		if text == "" {
+			keys := make([]string, 0, len(t))
+			for k := range t {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				collectReviews(t[k], now, out)
+			}
+			return
+		}
+
+		r := models.Review{
+			ID:           scalarString(t["id"]),
+			ReviewerName: firstString(t, "name"),
+			Language:     languageCode(firstString(t, "language")),
+			Text:         text,
+			HostResponse: firstString(t, "response"),
+		}
+		if reviewer, ok := t["reviewer"].(map[string]interface{}); ok {
+			r.ReviewerName = firstString(reviewer, "firstName", "smartName")
+		}
+		r.Date = parseReviewDate(firstString(t, "createdAt", "date", "localizedDate"), now)
+		if n, err := strconv.Atoi(scalarString(t["rating"])); err == nil && n >= 1 && n <= 5 {
+			r.Rating = n
+		}
+		*out = append(*out, r)
+	}
+}
+
+func dedupeReviews(in []models.Review) []models.Review {
+	seen := make(map[string]bool)
+	var out []models.Review
+	for _, r := range in {
+		key := r.ID
+		if key == "" {
+			key = r.ReviewerName + "\x00" + r.Text
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, r)
+	}
+	return out
+}
+
+var relativeDate = regexp.MustCompile(`(?i)\b([0-9]+|an?)\s+(day|week|month|year)s?\s+ago\b`)
+
+// parseReviewDate turns "2026-09-14T10:00:00Z", "September 2026", "3
+// weeks ago", "today" or "yesterday" into YYYY-MM-DD, or "".
+func parseReviewDate(raw string, now time.Time) string {
+	raw = strings.TrimSpace(raw)
+	if len(raw) >= 10 {
+		if t, err := time.Parse(config.DateLayout, raw[:10]); err == nil {
+			return t.Format(config.DateLayout)
+		}
+	}
+	if t, err := time.Parse("January 2006", raw); err == nil {
+		return t.Format(config.DateLayout)
+	}
+
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	lower := strings.ToLower(raw)
+	switch {
+	case lower == "today":
+		return day.Format(config.DateLayout)
+	case lower == "yesterday":
+		return day.AddDate(0, 0, -1).Format(config.DateLayout)
+	}
+	m := relativeDate.FindStringSubmatch(lower)
+	if m == nil {
+		return ""
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		n = 1 // "a week ago"
+	}
+	switch m[2] {
+	case "day":
+		day = day.AddDate(0, 0, -n)
+	case "week":
+		day = day.AddDate(0, 0, -7*n)
+	case "month":
+		day = day.AddDate(0, -n, 0)
+	case "year":
+		day = day.AddDate(-n, 0, 0)
+	}
+	return day.Format(config.DateLayout)
+}
+
+// languageNames maps the names Airbnb shows in "Translated from ..." to
+// language codes.
+var languageNames = map[string]string{
+	"arabic": "ar", "chinese": "zh", "dutch": "nl", "english": "en", "french": "fr",
+	"german": "de", "indonesian": "id", "italian": "it", "japanese": "ja", "korean": "ko",
+	"malay": "ms", "portuguese": "pt", "russian": "ru", "spanish": "es", "thai": "th",
+	"turkish": "tr", "vietnamese": "vi",
+}
+
+// languageCode normalizes "en", "en-US" or "Spanish" to a two-letter
+// code; unknown names give "".
+func languageCode(raw string) string {
+	lower := strings.ToLower(strings.TrimSpace(raw))
+	if code, ok := languageNames[lower]; ok {
+		return code
+	}
+	if code, _, _ := strings.Cut(lower, "-"); len(code) == 2 {
+		return code
+	}
+	return ""
+}
+
+var scorePattern = regexp.MustCompile(`^[0-5](?:[.,][0-9]+)?$`)
+
+// parseCategoryScores reads the reviews section's category ratings:
+// the API's [{"label": "Cleanliness", "localizedRating": "4.9"}] or the
+// section text, where each category is followed by its score on the
+// same line or the next.
+func parseCategoryScores(raw string) models.CategoryScores {
+	var scores models.CategoryScores
+
+	var lines []string
+	var v interface{}
+	if err := json.Unmarshal([]byte(raw), &v); err == nil {
+		items, _ := v.([]interface{})
+		for _, item := range items {
+			if obj, ok := item.(map[string]interface{}); ok {
+				label := firstString(obj, "label", "categoryType", "name")
+				value := scalarString(obj["localizedRating"])
+				if value == "" {
+					value = scalarString(obj["value"])
+				}
+				lines = append(lines, label, value)
+			}
+		}
+	} else {
+		lines = strings.Split(raw, "\n")
+	}
+
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		field := scoreField(&scores, line)
+		if field == nil {
+			continue
+		}
+		fields := strings.Fields(line)
+		value := fields[len(fields)-1]
+		if !scorePattern.MatchString(value) && i+1 < len(lines) {
+			value = strings.TrimSpace(lines[i+1])
+		}
+		if scorePattern.MatchString(value) {
+			*field, _ = strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+		}
+	}
+	return scores
+}
+
+// scoreField returns the score a category label names, or nil. Labels
+// are short ("Check-in", "CHECKIN"), which keeps review text out.
+func scoreField(s *models.CategoryScores, label string) *float64 {
+	lower := strings.ToLower(label)
+	if len(lower) > 24 {
+		return nil
+	}
+	switch {
+	case strings.HasPrefix(lower, "cleanliness"):
+		return &s.Cleanliness
+	case strings.HasPrefix(lower, "accuracy"):
+		return &s.Accuracy
+	case strings.HasPrefix(lower, "check-in"), strings.HasPrefix(lower, "checkin"), strings.HasPrefix(lower, "check in"):
+		return &s.CheckIn
+	case strings.HasPrefix(lower, "communication"):
+		return &s.Communication
+	case strings.HasPrefix(lower, "location"):
+		return &s.Location
+	case strings.HasPrefix(lower, "value"):
+		return &s.Value
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package airbnb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseReviewDate(t *testing.T) {
+	now := time.Date(2026, 3, 31, 22, 30, 0, 0, time.UTC)
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"2026-09-14T10:00:00Z", "2026-09-14"},
+		{"2026-09-14", "2026-09-14"},
+		{"September 2026", "2026-09-01"},
+		{" May 2025 ", "2025-05-01"},
+		{"today", "2026-03-31"},
+		{"Today", "2026-03-31"},
+		{"yesterday", "2026-03-30"},
+		{"3 days ago", "2026-03-28"},
+		{"a day ago", "2026-03-30"},
+		{"2 weeks ago", "2026-03-17"},
+		{"A week ago", "2026-03-24"},
+		{"1 month ago", "2026-03-03"}, // February has no 31st
+		{"11 months ago", "2025-05-01"},
+		{"2 years ago", "2024-03-31"},
+		{"Stayed a few nights", ""},
+		{"2026-13-01", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := parseReviewDate(tt.raw, now); got != tt.want {
+			t.Errorf("parseReviewDate(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestLanguageCode(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"en", "en"},
+		{"en-US", "en"},
+		{"PT-br", "pt"},
+		{"Spanish", "es"},
+		{" Malay ", "ms"},
+		{"Klingon", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := languageCode(tt.raw); got != tt.want {
+			t.Errorf("languageCode(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
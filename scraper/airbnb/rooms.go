@@ -0,0 +1,65 @@
+package airbnb
+
+import (
+	"airbnb-scraper/models"
+	"net/url"
+	"strconv"
+)
+
+// Room URLs collected from search pages carry per-session tracking
+// parameters (source_impression_id, federated_search_id, photo_id, random
+// check_in dates), so the same room shows up under many URLs. Identity is
+// the numeric room ID instead, and every room URL is rewritten to
+// <origin>/rooms/<id>.
+
+// roomIDString is the room ID of u as it appears in the URL, or "".
+func roomIDString(u string) string {
+	if id := models.RoomID(u); id != 0 {
+		return strconv.FormatInt(id, 10)
+	}
+	return ""
+}
+
+// CanonicalRoomURL rewrites a room URL to <origin>/rooms/<id>, keeping
+// the origin of u. URLs without a room ID are returned unchanged.
+func CanonicalRoomURL(u string) string {
+	id := roomIDString(u)
+	if id == "" {
+		return u
+	}
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.Host == "" {
+		return u
+	}
+	return parsed.Scheme + "://" + parsed.Host + "/rooms/" + id
+}
+
+// roomKey is the deduplication key of a property URL: its room ID, or
+// the URL itself when it has none.
+func roomKey(u string) string {
+	if id := roomIDString(u); id != "" {
+		return id
+	}
+	return u
+}
+
+// detailURL is the address loaded for a canonical room URL. In search
+// mode with dates the stay is added back, so the page shows the price
+// for the searched dates rather than a default stay.
+func (s *Scraper) detailURL(roomURL string) string {
+	if !s.cfg.SearchMode() || s.cfg.CheckIn == "" {
+		return roomURL
+	}
+	parsed, err := url.Parse(roomURL)
+	if err != nil {
+		return roomURL
+	}
+	q := parsed.Query()
+	q.Set("check_in", s.cfg.CheckIn)
+	q.Set("check_out", s.cfg.CheckOut)
+	if s.cfg.Adults > 0 {
+		q.Set("adults", strconv.Itoa(s.cfg.Adults))
+	}
+	parsed.RawQuery = q.Encode()
+	return parsed.String()
+}
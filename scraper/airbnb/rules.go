@@ -0,0 +1,251 @@
+package airbnb
+
+import (
+	"airbnb-scraper/models"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockPattern matches a time of day as Airbnb writes it: "3:00 PM",
+// "3 PM", "15:00", "noon". A bare number is not a time.
+const clockPattern = `([0-9]{1,2}:[0-9]{2}\s*(?:[ap]\.?m\.?)?|[0-9]{1,2}\s*[ap]\.?m\.?|noon|midnight)`
+
+var (
+	checkInRule  = regexp.MustCompile(`(?i)check-?in(?:\s+time)?(?:\s+is)?\s*:?\s*(?:after|from|between)?\s*` + clockPattern + `(?:\s*(?:-|–|—|to|and|until)\s*` + clockPattern + `)?`)
+	checkoutRule = regexp.MustCompile(`(?i)check-?out(?:\s+time)?(?:\s+is)?\s*:?\s*(?:before|by|until|at)?\s*` + clockPattern)
+	maxGuestRule = regexp.MustCompile(`(?i)([0-9]+)\s+guests?\s+max(?:imum)?|max(?:imum)?\s+(?:of\s+)?([0-9]+)\s+guests?`)
+
+	petsRule    = regexp.MustCompile(`(?i)\bpets?\b`)
+	smokingRule = regexp.MustCompile(`(?i)\bsmoking\b|\bsmoke-free\b`)
+	partiesRule = regexp.MustCompile(`(?i)\bpart(?:y|ies)\b|\bevents?\b`)
+	ruleDenied  = regexp.MustCompile(`(?i)\bno\b|not allowed|n't allowed|prohibited|forbidden|smoke-free`)
+	ruleAllowed = regexp.MustCompile(`(?i)allowed|permitted|welcome|friendly`)
+
+	smokeAlarmItem = regexp.MustCompile(`(?i)smoke (?:alarm|detector)`)
+	coAlarmItem    = regexp.MustCompile(`(?i)carbon monoxide|\bco (?:alarm|detector)`)
+	cameraItem     = regexp.MustCompile(`(?i)camera|recording device`)
+	itemMissing    = regexp.MustCompile(`(?i)\bno\b|not reported|not installed|without|n't have`)
+
+	cancellationName   = regexp.MustCompile(`(?i)\b(super strict|strict|firm|limited|moderate|flexible)\b`)
+	cancellationGrace  = regexp.MustCompile(`(?i)free cancellation for 48 hours`)
+	cancellationBefore = regexp.MustCompile(`(?i)free cancellation (?:before|until)\s+(?:[0-9:]+\s*[ap]\.?m\.?\s+on\s+)?([a-z]{3,9}\.?\s+[0-9]{1,2}|[0-9]{1,2}\s+[a-z]{3,9})(?:,?\s+([0-9]{4}))?`)
+	cancellationLead   = regexp.MustCompile(`(?i)(?:full refund|free cancellation)[^.]*?\b([0-9]+)\s+(hours?|days?)\s+(?:before|prior to) check-?in`)
+	cancellationRefund = regexp.MustCompile(`(?i)free cancellation|full refund|partial refund`)
+	cancellationNone   = regexp.MustCompile(`(?i)non-?refundable|no refunds?\b`)
+)
+
+// cancellationDateLayouts are the ways "Free cancellation before ..."
+// writes its date.
+var cancellationDateLayouts = []string{"Jan 2", "January 2", "2 Jan", "2 January"}
+
+// policyLines splits a policies value into lines: the strings of an API
+// or page state object (in key order), or the lines of page text.
+func policyLines(raw string) []string {
+	var v interface{}
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		return strings.Split(raw, "\n")
+	}
+	var lines []string
+	collectPolicyLines(v, &lines)
+	return lines
+}
+
+func collectPolicyLines(v interface{}, lines *[]string) {
+	switch t := v.(type) {
+	case []interface{}:
+		for _, item := range t {
+			collectPolicyLines(item, lines)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			collectPolicyLines(t[k], lines)
+		}
+	case string:
+		*lines = append(*lines, t)
+	}
+}
+
+// setHouseRules reads the house rules: check-in window and checkout time
+// ("Check-in: 3:00 PM - 10:00 PM", "Checkout before 11:00 AM"), the
+// guest maximum and whether pets, smoking and parties are allowed.
+func setHouseRules(l *models.Listing, raw string) {
+	p := &l.Policies
+	for _, line := range policyLines(raw) {
+		line = strings.Join(strings.Fields(line), " ")
+		if m := checkInRule.FindStringSubmatch(line); m != nil && p.CheckInFrom == "" {
+			p.CheckInFrom, p.CheckInUntil = parseClock(m[1]), parseClock(m[2])
+		}
+		if m := checkoutRule.FindStringSubmatch(line); m != nil && p.CheckoutBy == "" {
+			p.CheckoutBy = parseClock(m[1])
+		}
+		if m := maxGuestRule.FindStringSubmatch(line); m != nil && p.MaxGuests == 0 {
+			p.MaxGuests, _ = strconv.Atoi(m[1] + m[2])
+		}
+		setRule(&p.PetsAllowed, petsRule, line)
+		setRule(&p.SmokingAllowed, smokingRule, line)
+		setRule(&p.PartiesAllowed, partiesRule, line)
+	}
+}
+
+// setRule records what a house rule line says about subject, keeping the
+// first line that says something.
+func setRule(dst **bool, subject *regexp.Regexp, line string) {
+	if *dst != nil || !subject.MatchString(line) {
+		return
+	}
+	switch {
+	case ruleDenied.MatchString(line):
+		*dst = flag(false)
+	case ruleAllowed.MatchString(line):
+		*dst = flag(true)
+	}
+}
+
+// setSafety reads the safety & property items. Alarms are listed either
+// way ("Smoke alarm", "Carbon monoxide alarm not reported"); security
+// cameras are only listed when there are some, so a safety list without
+// them means none.
+func setSafety(l *models.Listing, raw string) {
+	p := &l.Policies
+	cameras := false
+	for _, line := range policyLines(raw) {
+		line = strings.Join(strings.Fields(line), " ")
+		present := !itemMissing.MatchString(line)
+		switch {
+		case smokeAlarmItem.MatchString(line) && p.SmokeAlarm == nil:
+			p.SmokeAlarm = flag(present)
+		case coAlarmItem.MatchString(line) && p.COAlarm == nil:
+			p.COAlarm = flag(present)
+		case cameraItem.MatchString(line):
+			cameras = cameras || present
+		}
+	}
+	p.SecurityCameras = flag(cameras)
+}
+
+// setCancellation classifies the cancellation policy into one of
+// models.CancellationTiers: by name when the text has one, otherwise by
+// how long before check-in cancelling is free (up to a day: flexible,
+// up to five days: moderate, longer or only 48 hours after booking:
+// strict). Dates without a year are taken as the last such date before
+// the listing's check-in, so a date-only policy needs the stay dates.
+func setCancellation(l *models.Listing, raw string) {
+	text := strings.Join(strings.Fields(raw), " ")
+	refunds := cancellationRefund.MatchString(text)
+
+	switch {
+	case cancellationNone.MatchString(text) && !refunds:
+		l.Policies.Cancellation = models.CancellationNonRefundable
+	case cancellationName.MatchString(text):
+		switch name := strings.ToLower(cancellationName.FindStringSubmatch(text)[1]); name {
+		case "flexible":
+			l.Policies.Cancellation = models.CancellationFlexible
+		case "moderate":
+			l.Policies.Cancellation = models.CancellationModerate
+		default:
+			l.Policies.Cancellation = models.CancellationStrict
+		}
+	case cancellationGrace.MatchString(text):
+		l.Policies.Cancellation = models.CancellationStrict
+	default:
+		if days, ok := freeCancellationDays(text, l.CheckIn); ok {
+			l.Policies.Cancellation = cancellationTier(days)
+		}
+	}
+}
+
+// freeCancellationDays is how many days before check-in cancelling stops
+// being free.
+func freeCancellationDays(text, checkIn string) (float64, bool) {
+	if m := cancellationLead.FindStringSubmatch(text); m != nil {
+		n, _ := strconv.ParseFloat(m[1], 64)
+		if strings.HasPrefix(strings.ToLower(m[2]), "hour") {
+			n /= 24
+		}
+		return n, true
+	}
+
+	m := cancellationBefore.FindStringSubmatch(text)
+	in, ok := parseDate(checkIn)
+	if m == nil || !ok {
+		return 0, false
+	}
+	date := strings.Join(strings.Fields(strings.ReplaceAll(m[1], ".", "")), " ")
+	for _, layout := range cancellationDateLayouts {
+		t, err := time.Parse(layout, date)
+		if err != nil {
+			continue
+		}
+		year := in.Year()
+		if m[2] != "" {
+			year, _ = strconv.Atoi(m[2])
+		} else if t.AddDate(year, 0, 0).After(in) {
+			year--
+		}
+		deadline := time.Date(year, t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return in.Sub(deadline).Hours() / 24, true
+	}
+	return 0, false
+}
+
+func cancellationTier(days float64) string {
+	switch {
+	case days <= 1:
+		return models.CancellationFlexible
+	case days <= 5:
+		return models.CancellationModerate
+	default:
+		return models.CancellationStrict
+	}
+}
+
+// parseClock turns a clockPattern match into "15:04", or "" when it is
+// empty or not a valid time.
+func parseClock(s string) string {
+	s = strings.ToLower(strings.ReplaceAll(strings.ReplaceAll(s, ".", ""), " ", ""))
+	switch s {
+	case "":
+		return ""
+	case "noon":
+		return "12:00"
+	case "midnight":
+		return "00:00"
+	}
+
+	suffix := ""
+	if strings.HasSuffix(s, "am") || strings.HasSuffix(s, "pm") {
+		s, suffix = s[:len(s)-2], s[len(s)-2:]
+	}
+	hour, minute := s, "0"
+	if i := strings.Index(s, ":"); i >= 0 {
+		hour, minute = s[:i], s[i+1:]
+	}
+	h, errH := strconv.Atoi(hour)
+	m, errM := strconv.Atoi(minute)
+	if errH != nil || errM != nil || m > 59 {
+		return ""
+	}
+	switch {
+	case suffix != "" && (h < 1 || h > 12):
+		return ""
+	case suffix == "am" && h == 12:
+		h = 0
+	case suffix == "pm" && h != 12:
+		h += 12
+	case h > 23:
+		return ""
+	}
+	return fmt.Sprintf("%02d:%02d", h, m)
+}
+
+func flag(v bool) *bool { return &v }
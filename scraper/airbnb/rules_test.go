@@ -0,0 +1,74 @@
+package airbnb
+
+import (
+	"airbnb-scraper/models"
+	"testing"
+)
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"3:00 PM", "15:00"},
+		{"3 PM", "15:00"},
+		{"3pm", "15:00"},
+		{"3 p.m.", "15:00"},
+		{"11:59 PM", "23:59"},
+		{"12:30 pm", "12:30"},
+		{"12 AM", "00:00"},
+		{"9:15 a.m.", "09:15"},
+		{"15:00", "15:00"},
+		{"0:30", "00:30"},
+		{"noon", "12:00"},
+		{"Midnight", "00:00"},
+		{"", ""},
+		{"13 PM", ""},
+		{"0 AM", ""},
+		{"24:00", ""},
+		{"10:75", ""},
+		{"soon", ""},
+	}
+	for _, tt := range tests {
+		if got := parseClock(tt.in); got != tt.want {
+			t.Errorf("parseClock(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSetCancellation(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		checkIn string
+		want    string
+	}{
+		{"flexible by name", "Flexible: full refund up to 1 day before check-in", "", models.CancellationFlexible},
+		{"moderate by name", "Moderate", "", models.CancellationModerate},
+		{"firm is strict", "Firm cancellation policy", "", models.CancellationStrict},
+		{"limited is strict", "Limited", "", models.CancellationStrict},
+		{"super strict", "Super Strict 30 Days", "", models.CancellationStrict},
+		{"non-refundable", "This reservation is non-refundable.", "", models.CancellationNonRefundable},
+		{"no refunds", "No refunds", "", models.CancellationNonRefundable},
+		{"48 hour grace", "Free cancellation for 48 hours. Cancel before Nov 1 for a partial refund.", "", models.CancellationStrict},
+		{"lead in hours", "Full refund if you cancel 24 hours before check-in.", "", models.CancellationFlexible},
+		{"lead in days", "Free cancellation up to 5 days before check-in", "", models.CancellationModerate},
+		{"long lead", "Free cancellation until 14 days prior to check-in", "", models.CancellationStrict},
+		{"date a day before", "Free cancellation before Nov 7", "2026-11-08", models.CancellationFlexible},
+		{"date with time", "Free cancellation before 2:00 PM on Nov 3", "2026-11-08", models.CancellationModerate},
+		{"date across the new year", "Free cancellation before Dec 20", "2027-01-05", models.CancellationStrict},
+		{"day first with year", "Free cancellation before 1 November, 2026", "2026-11-08", models.CancellationStrict},
+		{"date without stay dates", "Free cancellation before Nov 7", "", ""},
+		{"unknown wording", "Cancel before check-in for a partial refund", "2026-11-08", ""},
+		{"empty", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := models.Listing{CheckIn: tt.checkIn}
+			setCancellation(&l, tt.raw)
+			if got := l.Policies.Cancellation; got != tt.want {
+				t.Errorf("setCancellation(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"airbnb-scraper/models"
 	"airbnb-scraper/utils"
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -19,39 +22,154 @@ type Scraper struct {
 	allocCancel context.CancelFunc
 	seenURLs    map[string]bool
 	mu          sync.Mutex
+	selectors   *SelectorSet
+	fixtures    *fixtureStore // nil unless cfg.FixturesMode is set
+	photos      *photoStore   // nil unless cfg.PhotosDir is set
+
+	// searchResults holds what StaysSearch responses said about each
+	// room (keyed by room ID); detail scrapes use it to fill gaps.
+	searchResults map[string]searchResult
+
+	// hostListings holds each host's profile lookup (by host ID), so a
+	// profile page is opened once per host and run.
+	hostListings map[int64]*hostLookup
 }
 
-func NewScraper(cfg *config.Config) (*Scraper, error) {
+// NewScraper starts Chrome for a run. Once ctx is cancelled the browser
+// stays up for cfg.ShutdownTimeout more, so pages already loading can
+// finish; callers stop handing out new work as soon as ctx is done.
+func NewScraper(ctx context.Context, cfg *config.Config) (*Scraper, error) {
+	selectors, err := LoadSelectorSet(cfg.SelectorsPath)
+	if err != nil {
+		return nil, err
+	}
+	utils.Info("Selector set %s (%s)", selectors.Version, selectors.Source)
+
+	opts := utils.StealthOpts(cfg.Headless)
+
+	var fixtures *fixtureStore
+	if cfg.FixturesMode != "" {
+		fixtures, err = newFixtureStore(cfg.FixturesDir, cfg.BaseURL)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.FixturesMode == config.FixturesReplay {
+			if err := fixtures.serve(); err != nil {
+				return nil, err
+			}
+			// Everything except the local fixture server is unreachable,
+			// so a replay can never silently fall back to the live site.
+			opts = append(opts, chromedp.Flag("host-resolver-rules", "MAP * ~NOTFOUND , EXCLUDE 127.0.0.1"))
+			utils.Info("Replaying fixtures from %s via %s", cfg.FixturesDir, fixtures.localURL)
+		} else {
+			utils.Info("Recording fixtures to %s", cfg.FixturesDir)
+		}
+	}
+
+	// Replays stay offline, so photos are only recorded, not downloaded;
+	// cards-only runs never see the photos at all.
+	var photos *photoStore
+	if cfg.PhotosDir != "" && cfg.FixturesMode != config.FixturesReplay && !cfg.CardsOnly {
+		photos, err = newPhotoStore(cfg.PhotosDir, cfg.RequestTimeout)
+		if err != nil {
+			return nil, err
+		}
+		utils.Info("Downloading photos to %s", cfg.PhotosDir)
+	}
+
 	utils.Info("Launching Chrome browser...")
-	allocCtx, allocCancel := chromedp.NewExecAllocator(
-		context.Background(),
-		utils.StealthOpts(cfg.Headless)...,
+	drainCtx, drainCancel := utils.WithGrace(ctx, cfg.ShutdownTimeout)
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(
+		drainCtx,
+		opts...,
 	)
+	allocCancel := func() {
+		cancelAlloc()
+		drainCancel()
+	}
 	utils.Success("Browser ready")
 	return &Scraper{
 		cfg:         cfg,
 		allocCtx:    allocCtx,
 		allocCancel: allocCancel,
 		seenURLs:    make(map[string]bool),
+		selectors:   selectors,
+		fixtures:    fixtures,
+		photos:      photos,
+
+		searchResults: make(map[string]searchResult),
+		hostListings:  make(map[int64]*hostLookup),
 	}, nil
 }
 
 func (s *Scraper) Close() {
 	utils.Info("Closing browser...")
 	s.allocCancel()
+	if s.fixtures != nil {
+		s.fixtures.close()
+	}
 }
 
+// markSeenIfNew claims a property for scraping. URLs are compared by room
+// ID, so the same room reached through different tracking URLs is only
+// scraped once.
 func (s *Scraper) markSeenIfNew(url string) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.seenURLs[url] {
+	key := roomKey(url)
+	if s.seenURLs[key] {
 		return false
 	}
-	s.seenURLs[url] = true
+	s.seenURLs[key] = true
 	return true
 }
 
+func (s *Scraper) rememberSearchResults(results []searchResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range results {
+		s.searchResults[r.RoomID] = r
+	}
+}
+
+func (s *Scraper) searchResultFor(propertyURL string) (searchResult, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.searchResults[roomIDString(propertyURL)]
+	return r, ok
+}
+
+// origin is the origin of cfg.BaseURL, used to make relative links
+// absolute. Pointing BaseURL at another host (a mock site, a regional
+// domain) keeps the crawl on that host.
+func (s *Scraper) origin() string {
+	if u, err := url.Parse(s.cfg.BaseURL); err == nil && u.Host != "" {
+		return u.Scheme + "://" + u.Host
+	}
+	return "https://www.airbnb.com"
+}
+
+// originJS is origin as a JavaScript string literal.
+func (s *Scraper) originJS() string {
+	b, _ := json.Marshal(s.origin())
+	return string(b)
+}
+
+// navigate loads u in the tab and fails on an HTTP error status. Chrome
+// renders 403/429 pages like any other, so without this check a blocked
+// request would only surface as a selector timeout.
+func navigate(ctx context.Context, u string) error {
+	resp, err := chromedp.RunResponse(ctx, chromedp.Navigate(u))
+	if err != nil {
+		return err
+	}
+	if resp != nil && resp.Status >= 400 {
+		return fmt.Errorf("HTTP %d %s", resp.Status, resp.StatusText)
+	}
+	return nil
+}
+
 func (s *Scraper) GetSectionURLs() ([]string, error) {
 	utils.Info("Opening homepage to collect section URLs...")
 
@@ -61,10 +179,13 @@ func (s *Scraper) GetSectionURLs() ([]string, error) {
 	ctx, cancel := context.WithTimeout(tabCtx, 90*time.Second)
 	defer cancel()
 
+	if err := navigate(ctx, s.pageURL(s.cfg.BaseURL)); err != nil {
+		return nil, fmt.Errorf("homepage error: %w", err)
+	}
+
 	var hrefs []string
 
 	err := chromedp.Run(ctx,
-		chromedp.Navigate(s.cfg.BaseURL),
 		utils.HideWebDriver(),
 		chromedp.Sleep(5*time.Second),
 		chromedp.Evaluate(`window.scrollTo(0, document.body.scrollHeight * 0.35)`, nil),
@@ -72,10 +193,11 @@ func (s *Scraper) GetSectionURLs() ([]string, error) {
 		chromedp.Evaluate(`window.scrollTo(0, document.body.scrollHeight * 0.7)`, nil),
 		chromedp.Sleep(4*time.Second),
 		chromedp.Evaluate(`(() => {
+			const origin = `+s.originJS()+`;
 			const toAbs = (href) => {
 				if (!href) return '';
 				if (href.startsWith('http')) return href;
-				if (href.startsWith('/')) return 'https://www.airbnb.com' + href;
+				if (href.startsWith('/')) return origin + href;
 				return '';
 			};
 
@@ -105,6 +227,8 @@ func (s *Scraper) GetSectionURLs() ([]string, error) {
 		return nil, fmt.Errorf("homepage error: %w", err)
 	}
 
+	s.recordPage(ctx, s.cfg.BaseURL)
+
 	if len(hrefs) == 0 {
 		return nil, fmt.Errorf("no section URLs found")
 	}
@@ -113,18 +237,51 @@ func (s *Scraper) GetSectionURLs() ([]string, error) {
 	return hrefs, nil
 }
 
-func (s *Scraper) GetPropertyURLsFromSection(sectionURL string) ([]string, error) {
+// GetPropertyURLsFromSection collects property URLs from a section,
+// following "Next" until MaxSectionPages. Cancelling ctx stops the
+// pagination after the current page.
+func (s *Scraper) GetPropertyURLsFromSection(ctx context.Context, sectionURL string) ([]string, error) {
+	cards, err := s.collectCards(ctx, sectionURL)
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]string, len(cards))
+	for i, c := range cards {
+		urls[i] = c.URL
+	}
+	utils.Success("Got %d property URLs from section", len(urls))
+	return urls, nil
+}
+
+// collectCards reads the listing cards of a section, one per room,
+// following "Next" until MaxSectionPages. Cancelling ctx stops the
+// pagination after the current page.
+func (s *Scraper) collectCards(ctx context.Context, sectionURL string) ([]searchCard, error) {
 	tabCtx, tabCancel := chromedp.NewContext(s.allocCtx)
 	defer tabCancel()
 
-	ctx, cancel := context.WithTimeout(tabCtx, s.cfg.RequestTimeout)
+	// Every extra results page costs another navigation, so the section
+	// gets one RequestTimeout per page it is allowed to visit.
+	maxPages := s.cfg.MaxSectionPages
+	if maxPages < 1 {
+		maxPages = 1
+	}
+	pageCtx, cancel := context.WithTimeout(tabCtx, s.cfg.RequestTimeout*time.Duration(maxPages))
 	defer cancel()
 
-	var urls []string
+	var cards []searchCard
 	seen := make(map[string]bool)
 
-	err := chromedp.Run(ctx,
-		chromedp.Navigate(sectionURL),
+	// Search responses carry exact prices, ratings and coordinates for
+	// every card; keep them so the detail scrape can fill gaps.
+	capture := captureAPI(tabCtx, opStaysSearch)
+	s.replayAPI(sectionURL, capture)
+
+	if err := navigate(pageCtx, s.pageURL(sectionURL)); err != nil {
+		return nil, fmt.Errorf("failed to get property URLs: %w", err)
+	}
+
+	err := chromedp.Run(pageCtx,
 		utils.HideWebDriver(),
 		chromedp.WaitVisible(`[data-testid="listing-card-title"]`, chromedp.ByQuery),
 		chromedp.Sleep(3*time.Second),
@@ -134,107 +291,169 @@ func (s *Scraper) GetPropertyURLsFromSection(sectionURL string) ([]string, error
 		return nil, fmt.Errorf("failed to get property URLs: %w", err)
 	}
 
-	extractTopTwo := func() ([]string, error) {
-		var pageURLs []string
-		err := chromedp.Run(ctx, chromedp.Evaluate(`
-			Array.from(document.querySelectorAll('[data-testid="listing-card-title"]'))
-				.slice(0, 5)
+	// The first results page is server-rendered into the page state
+	// rather than fetched through StaysSearch.
+	var searchRoots []interface{}
+	if data, err := collectStructuredData(pageCtx); err == nil {
+		searchRoots = append(searchRoots, data.state...)
+	}
+	defer func() {
+		results := parseSearchResults(append(searchRoots, capture.payloads(opStaysSearch)...))
+		s.rememberSearchResults(results)
+		s.recordAPI(sectionURL, capture)
+	}()
+	s.recordPage(pageCtx, sectionURL)
+
+	// CardsPerPage <= 0 means "every card on the page".
+	extractCards := func() ([]searchCard, error) {
+		var pageCards []searchCard
+		err := chromedp.Run(pageCtx, chromedp.Evaluate(fmt.Sprintf(`(() => {
+			const limit = %d;
+			const origin = %s;
+			const toAbs = (href) => href.startsWith('/') ? origin + href : href;
+			const text = (el) => el ? el.textContent.replace(/\s+/g, ' ').trim() : '';
+			// The innermost element whose text matches, so a price or
+			// rating is not read together with the rest of the card.
+			const innermost = (card, test) => {
+				const hits = Array.from(card.querySelectorAll('span, div')).filter(el => test(text(el)));
+				return hits.find(el => !hits.some(o => o !== el && el.contains(o)));
+			};
+			const titles = Array.from(document.querySelectorAll('[data-testid="listing-card-title"]'));
+			return (limit > 0 ? titles.slice(0, limit) : titles)
 				.map(titleEl => {
 					const card = titleEl.closest('div[itemprop="itemListElement"]') || titleEl.closest('div');
-					if (!card) return '';
+					if (!card) return null;
 					const linkEl = card.querySelector('a[href*="/rooms/"]');
-					if (!linkEl) return '';
-					const href = linkEl.getAttribute('href') || '';
-					return href.startsWith('/') ? 'https://www.airbnb.com' + href : href;
+					if (!linkEl) return null;
+					const price = card.querySelector('[data-testid="price-availability-row"]') ||
+						innermost(card, t => /([$€£]|RM)\s*[0-9]/.test(t) && /night|total/i.test(t));
+					const rating = innermost(card, t => /^[0-9](\.[0-9]+)?\s*\([0-9,]+\)$/.test(t) || t === 'New');
+					const img = card.querySelector('img');
+					return {
+						url: toAbs(linkEl.getAttribute('href') || ''),
+						title: text(card.querySelector('[data-testid="listing-card-name"]')) || text(titleEl),
+						price: text(price),
+						rating: text(rating),
+						thumbnail: img ? toAbs(img.getAttribute('src') || '') : ''
+					};
 				})
-				.filter(u => u !== '')
-		`, &pageURLs))
-		return pageURLs, err
+				.filter(c => c && c.url !== '');
+		})()`, s.cfg.CardsPerPage, s.originJS()), &pageCards))
+		return pageCards, err
 	}
 
-	addUnique := func(candidates []string) {
-		for _, u := range candidates {
-			if !seen[u] {
-				seen[u] = true
-				urls = append(urls, u)
+	// Cards link to tracking URLs; keep one card per room, under its
+	// canonical URL.
+	addUnique := func(candidates []searchCard) int {
+		added := 0
+		for _, c := range candidates {
+			c.URL = CanonicalRoomURL(c.URL)
+			if !seen[c.URL] {
+				seen[c.URL] = true
+				cards = append(cards, c)
+				added++
 			}
 		}
+		return added
 	}
 
-	page1URLs, err := extractTopTwo()
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse page 1 property URLs: %w", err)
+	clickNext := func() (bool, error) {
+		var moved bool
+		err := chromedp.Run(pageCtx,
+			chromedp.Evaluate(`(() => {
+				const selectors = [
+					'a[aria-label*="Next"]',
+					'button[aria-label*="Next"]',
+					'a[aria-label*="next"]',
+					'button[aria-label*="next"]'
+				];
+				for (const sel of selectors) {
+					const el = document.querySelector(sel);
+					if (el && !el.disabled && el.getAttribute('aria-disabled') !== 'true') {
+						el.click();
+						return true;
+					}
+				}
+				return false;
+			})()`, &moved),
+		)
+		return moved, err
 	}
-	addUnique(page1URLs)
 
-	var movedToPage2 bool
-	err = chromedp.Run(ctx,
-		chromedp.Evaluate(`(() => {
-			const selectors = [
-				'a[aria-label*="Next"]',
-				'button[aria-label*="Next"]',
-				'a[aria-label*="next"]',
-				'button[aria-label*="next"]'
-			];
-			for (const sel of selectors) {
-				const el = document.querySelector(sel);
-				if (el) {
-					el.click();
-					return true;
-				}
+	for page := 1; ; page++ {
+		pageCards, err := extractCards()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse page %d property URLs: %w", page, err)
+		}
+
+		if addUnique(pageCards) == 0 {
+			if page > 1 {
+				utils.Warn("Section page %d yielded no new property URLs; stopping pagination", page)
 			}
-			return false;
-		})()`, &movedToPage2),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to move to page 2: %w", err)
-	}
+			break
+		}
 
-	if movedToPage2 {
-		err = chromedp.Run(ctx,
-			chromedp.Sleep(4*time.Second),
-			chromedp.WaitVisible(`[data-testid="listing-card-title"]`, chromedp.ByQuery),
-		)
+		if page >= maxPages {
+			break
+		}
+		if ctx.Err() != nil {
+			utils.Warn("Shutting down; not following section page %d", page+1)
+			break
+		}
+
+		moved, err := clickNext()
 		if err != nil {
-			return nil, fmt.Errorf("page 2 did not load: %w", err)
+			return nil, fmt.Errorf("failed to move to page %d: %w", page+1, err)
+		}
+		if !moved {
+			utils.Warn("No Next button after section page %d; stopping pagination", page)
+			break
 		}
 
-		page2URLs, err := extractTopTwo()
+		err = chromedp.Run(pageCtx,
+			chromedp.Sleep(4*time.Second),
+			chromedp.WaitVisible(`[data-testid="listing-card-title"]`, chromedp.ByQuery),
+		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse page 2 property URLs: %w", err)
+			return nil, fmt.Errorf("page %d did not load: %w", page+1, err)
 		}
-		addUnique(page2URLs)
-	} else {
-		utils.Warn("Could not find Next button for section pagination; using only page 1")
+		s.recordCurrentPage(pageCtx)
 	}
 
-	utils.Success("Got %d property URLs from section", len(urls))
-	return urls, nil
+	return cards, nil
 }
 
-func (s *Scraper) ScrapePropertyPage(propertyURL string) (models.Listing, error) {
+// ScrapePropertyPage scrapes one detail page. Cancelling ctx skips the
+// page if it has not started loading yet and stops further retries; a
+// load already under way runs on until the browser's shutdown deadline.
+func (s *Scraper) ScrapePropertyPage(ctx context.Context, propertyURL string) (models.Listing, error) {
 	if !s.markSeenIfNew(propertyURL) {
 		return models.Listing{}, nil
 	}
 
-	utils.RandomDelay(s.cfg.MinDelay, s.cfg.MaxDelay)
-
 	var listing models.Listing
-	var err error
 
-	err = utils.Retry(s.cfg.MaxRetries, func() error {
-		listing, err = s.extractFromPropertyPage(propertyURL)
-		return err
-	})
+	err := utils.RandomDelay(ctx, s.cfg.MinDelay, s.cfg.MaxDelay)
+	if err == nil {
+		err = utils.Retry(ctx, s.cfg.MaxRetries, func() error {
+			var err error
+			listing, err = s.extractFromPropertyPage(propertyURL)
+			return err
+		})
+	}
 
 	if err != nil {
 		s.mu.Lock()
-		delete(s.seenURLs, propertyURL)
+		delete(s.seenURLs, roomKey(propertyURL))
 		s.mu.Unlock()
 		return models.Listing{}, err
 	}
 
-	utils.Success("✓ %s | $%.0f | %.2f★", truncate(listing.Title, 30), listing.Price, listing.Rating)
+	s.completeHost(ctx, &listing.Host)
+	s.downloadPhotos(ctx, &listing)
+
+	utils.Success("✓ %s | $%.0f | %.2f★ (%d reviews)", truncate(listing.Title, 30), listing.Price, listing.Rating, listing.ReviewCount)
+	utils.Info("  fields: %s", s.selectors.formatSources(listing.Sources))
 	return listing, nil
 }
 
@@ -245,119 +464,73 @@ func (s *Scraper) extractFromPropertyPage(propertyURL string) (models.Listing, e
 	ctx, cancel := context.WithTimeout(tabCtx, s.cfg.RequestTimeout)
 	defer cancel()
 
-	var title, price, location, rating, description string
+	capture := captureAPI(tabCtx, opStaysPdpSections, opStaysPdpReviews, opPdpAvailabilityCalendar)
+	pageURL := s.detailURL(propertyURL)
+	s.replayAPI(pageURL, capture)
+
+	if err := navigate(ctx, s.pageURL(pageURL)); err != nil {
+		return models.Listing{}, fmt.Errorf("chromedp failed: %w", err)
+	}
 
 	err := chromedp.Run(ctx,
-		chromedp.Navigate(propertyURL),
 		utils.HideWebDriver(),
-		
 		chromedp.WaitVisible(`h1`, chromedp.ByQuery),
-		chromedp.Sleep(4*time.Second),
-
-		chromedp.Evaluate(`
-			document.querySelector('h1')?.textContent.trim() || ''
-		`, &title),
-
-		chromedp.Evaluate(`
-			(() => {
-				const firstMoney = (txt) => {
-					if (!txt) return '';
-					const m = txt.match(/\$?\s*([0-9][0-9,]*(?:\.[0-9]+)?)/);
-					return m ? ('$' + m[1]) : '';
-				};
-
-				const ariaPrice = Array.from(document.querySelectorAll('[aria-label]')).find(el =>
-					/\$\s*[0-9]/.test(el.getAttribute('aria-label') || '') &&
-					/for\s+[0-9]+\s+nights?/i.test(el.getAttribute('aria-label') || '')
-				);
-				const fromAria = firstMoney(ariaPrice ? ariaPrice.getAttribute('aria-label') : '');
-				if (fromAria) return fromAria;
-
-				const visibleTotal = document.querySelector('span.u1opajno');
-				const fromVisibleTotal = firstMoney(visibleTotal ? visibleTotal.textContent : '');
-				if (fromVisibleTotal) return fromVisibleTotal;
-
-				const fromOld = firstMoney(document.querySelector('.u174bpcy')?.textContent || '');
-				if (fromOld) return fromOld;
-
-				return '';
-			})()
-		`, &price),
-
-		chromedp.Evaluate(`
-			(() => {
-				let h2 = document.querySelector('h2.hpipapi');
-				if (!h2) return '';
-				let text = h2.textContent.trim();
-				let match = text.match(/in (.+?)$/);
-				return match ? match[1] : text;
-			})()
-		`, &location),
-
-		chromedp.Evaluate(`
-			(() => {
-				const normalize = (txt) => {
-					if (!txt) return '';
-					const m = txt.match(/([0-9]+(?:\.[0-9]+)?)/);
-					return m ? m[1] : '';
-				};
-
-				const banner = document.querySelector('[data-testid="pdp-reviews-highlight-banner-host-rating"]');
-				if (banner) {
-					const ratingDiv = banner.querySelector('div[aria-hidden="true"]');
-					const fromBanner = normalize(ratingDiv ? ratingDiv.textContent.trim() : '');
-					if (fromBanner) return fromBanner;
-				}
-
-				const ratedTextEl = Array.from(document.querySelectorAll('span')).find(el =>
-					/Rated\s+[0-9]+(?:\.[0-9]+)?\s+out of 5 stars\./i.test(el.textContent || '')
-				);
-				const fromRatedText = normalize(ratedTextEl ? ratedTextEl.textContent.trim() : '');
-				if (fromRatedText) return fromRatedText;
-
-				const altDiv = document.querySelector('div.rmtgcc3[aria-hidden="true"]');
-				const fromAltDiv = normalize(altDiv ? altDiv.textContent.trim() : '');
-				if (fromAltDiv) return fromAltDiv;
-
-				return '';
-			})()
-		`, &rating),
-
-		chromedp.Evaluate(`
-			(() => {
-				const clean = (txt) => (txt || '').replace(/\s+/g, ' ').trim();
+	)
+	if err != nil {
+		return models.Listing{}, fmt.Errorf("chromedp failed: %w", err)
+	}
 
-				const preferred = document.querySelector(
-					'[data-section-id="DESCRIPTION_DEFAULT"] span.l1h825yc, [data-plugin-in-point-id="DESCRIPTION_DEFAULT"] span.l1h825yc'
-				);
-				const fromPreferred = clean(preferred ? (preferred.innerText || preferred.textContent) : '');
-				if (fromPreferred) return fromPreferred;
+	// The sections API response is what fills the booking sidebar, so
+	// once it has arrived the page is ready; 4s is only the upper bound.
+	capture.wait(ctx, opStaysPdpSections, 4*time.Second)
+	s.recordPage(ctx, pageURL)
+	s.recordAPI(pageURL, capture)
 
-				const section = document.querySelector(
-					'[data-section-id="DESCRIPTION_DEFAULT"], [data-plugin-in-point-id="DESCRIPTION_DEFAULT"]'
-				);
-				const fromSection = clean(section ? (section.innerText || section.textContent) : '');
-				if (fromSection) return fromSection;
+	data, err := collectStructuredData(ctx)
+	if err != nil {
+		utils.Warn("%v — using API and DOM data only", err)
+		data = &structuredData{}
+	}
+	data.api = capture.payloads(opStaysPdpSections)
 
-				return clean(document.querySelector('[data-testid="listing-page-summary"]')?.textContent || '');
-			})()
-		`, &description),
-	)
+	page := &Page{ctx: ctx, data: data}
+	if r, ok := s.searchResultFor(propertyURL); ok {
+		page.search = &r
+	}
 
+	listing, err := s.selectors.Extract(page)
 	if err != nil {
-		return models.Listing{}, fmt.Errorf("chromedp failed: %w", err)
+		return models.Listing{}, fmt.Errorf("extraction failed: %w", err)
 	}
 
-	return models.Listing{
-		Platform:    "airbnb",
-		Title:       strings.TrimSpace(title),
-		RawPrice:    price,
-		Price:       parsePrice(price),
-		Location:    strings.TrimSpace(location),
-		Rating:      parseRating(rating),
-		URL:         propertyURL,
-		Description: truncate(strings.TrimSpace(description), 200),
-	}, nil
+	listing.Platform = "airbnb"
+	listing.ID = models.RoomID(propertyURL)
+	listing.URL = CanonicalRoomURL(propertyURL)
+	resolvePhotos(listing.Photos, pageURL)
+	if page.search != nil {
+		listing.Thumbnail = s.thumbnailURL(page.search.Thumbnail)
+	}
+	if listing.Thumbnail == "" && len(listing.Photos) > 0 {
+		listing.Thumbnail = listing.Photos[0].URL
+	}
+
+	// A failed calendar or review stage keeps the listing; it only warns.
+	if s.cfg.CalendarMonths > 0 {
+		listing.Calendar, err = s.scrapeCalendar(tabCtx, capture)
+		if err != nil {
+			utils.Warn("Calendar of %s: %v", listing.URL, err)
+		}
+	}
+	if s.cfg.MaxReviews > 0 {
+		listing.Reviews, err = s.scrapeReviews(tabCtx, capture)
+		if err != nil {
+			utils.Warn("Reviews of %s: %v", listing.URL, err)
+		}
+	}
+	if s.cfg.CalendarMonths > 0 || s.cfg.MaxReviews > 0 {
+		s.recordAPI(pageURL, capture)
+	}
+	return listing, nil
 }
 
 func parsePrice(raw string) float64 {
@@ -383,6 +556,28 @@ func parseRating(raw string) float64 {
 	return v
 }
 
+// parseCount reads a count such as "1,234" or "128 reviews".
+func parseCount(raw string) int {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		if r == ',' {
+			return -1
+		}
+		return ' '
+	}, raw)
+	fields := strings.Fields(digits)
+	if len(fields) == 0 {
+		return 0
+	}
+	n, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
 func truncate(s string, max int) string {
 	runes := []rune(s)
 	if len(runes) <= max {
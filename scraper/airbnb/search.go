@@ -0,0 +1,75 @@
+package airbnb
+
+import (
+	"airbnb-scraper/config"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// BuildSearchURL turns the search settings in cfg into an Airbnb
+// search page URL, e.g.
+//
+//	https://www.airbnb.com/s/Kuala-Lumpur--Malaysia/homes?query=Kuala+Lumpur%2C+Malaysia&checkin=2026-05-08&checkout=2026-05-10&adults=2
+//
+// The result is fed into GetPropertyURLsFromSection exactly like a
+// section link collected from the homepage.
+func BuildSearchURL(cfg *config.Config) (string, error) {
+	location := strings.TrimSpace(cfg.SearchLocation)
+	if location == "" {
+		return "", fmt.Errorf("search location is empty")
+	}
+
+	base, err := url.Parse(cfg.BaseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL %q: %w", cfg.BaseURL, err)
+	}
+
+	if err := cfg.ValidateSearch(); err != nil {
+		return "", err
+	}
+
+	q := url.Values{}
+	q.Set("query", location)
+	if cfg.CheckIn != "" {
+		q.Set("checkin", cfg.CheckIn)
+		q.Set("checkout", cfg.CheckOut)
+	}
+	if cfg.Adults > 0 {
+		q.Set("adults", strconv.Itoa(cfg.Adults))
+	}
+	if cfg.Children > 0 {
+		q.Set("children", strconv.Itoa(cfg.Children))
+	}
+	if cfg.Pets > 0 {
+		q.Set("pets", strconv.Itoa(cfg.Pets))
+	}
+	if cfg.MinPrice > 0 {
+		q.Set("price_min", strconv.Itoa(cfg.MinPrice))
+	}
+	if cfg.MaxPrice > 0 {
+		q.Set("price_max", strconv.Itoa(cfg.MaxPrice))
+	}
+
+	slug := locationSlug(location)
+	searchURL := url.URL{
+		Scheme:   base.Scheme,
+		Host:     base.Host,
+		Path:     "/s/" + slug + "/homes",
+		RawPath:  "/s/" + url.PathEscape(slug) + "/homes",
+		RawQuery: q.Encode(),
+	}
+	return searchURL.String(), nil
+}
+
+// locationSlug converts "Kuala Lumpur, Malaysia" into the path segment
+// Airbnb uses for it: "Kuala-Lumpur--Malaysia". The slug is unescaped;
+// a "/" in it belongs to the segment, not the path.
+func locationSlug(location string) string {
+	parts := strings.Split(location, ",")
+	for i, p := range parts {
+		parts[i] = strings.Join(strings.Fields(p), "-")
+	}
+	return strings.Join(parts, "--")
+}
@@ -0,0 +1,75 @@
+package airbnb
+
+import (
+	"airbnb-scraper/config"
+	"testing"
+)
+
+func TestBuildSearchURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     func(c *config.Config)
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "location only",
+			set:  func(c *config.Config) { c.Adults = 0 },
+			want: "https://www.airbnb.com/s/Kuala-Lumpur--Malaysia/homes?query=Kuala+Lumpur%2C+Malaysia",
+		},
+		{
+			name: "dates and guests",
+			set: func(c *config.Config) {
+				c.CheckIn, c.CheckOut = "2026-05-08", "2026-05-10"
+				c.Adults, c.Children, c.Pets = 2, 1, 1
+			},
+			want: "https://www.airbnb.com/s/Kuala-Lumpur--Malaysia/homes?adults=2&checkin=2026-05-08&checkout=2026-05-10&children=1&pets=1&query=Kuala+Lumpur%2C+Malaysia",
+		},
+		{
+			name: "price bounds",
+			set:  func(c *config.Config) { c.MinPrice, c.MaxPrice = 50, 200 },
+			want: "https://www.airbnb.com/s/Kuala-Lumpur--Malaysia/homes?adults=1&price_max=200&price_min=50&query=Kuala+Lumpur%2C+Malaysia",
+		},
+		{
+			name: "extra spaces and path of the base URL",
+			set: func(c *config.Config) {
+				c.BaseURL = "https://www.airbnb.co.uk/some/page?x=1"
+				c.SearchLocation = "  Bali ,  Indonesia "
+			},
+			want: "https://www.airbnb.co.uk/s/Bali--Indonesia/homes?adults=1&query=Bali+%2C++Indonesia",
+		},
+		{
+			name: "slug escaping",
+			set:  func(c *config.Config) { c.SearchLocation = "São Paulo/SP" },
+			want: "https://www.airbnb.com/s/S%C3%A3o-Paulo%2FSP/homes?adults=1&query=S%C3%A3o+Paulo%2FSP",
+		},
+		{name: "empty location", set: func(c *config.Config) { c.SearchLocation = "  " }, wantErr: true},
+		{name: "check-in without check-out", set: func(c *config.Config) { c.CheckIn = "2026-05-08" }, wantErr: true},
+		{name: "check-out before check-in", set: func(c *config.Config) { c.CheckIn, c.CheckOut = "2026-05-10", "2026-05-08" }, wantErr: true},
+		{name: "bad date", set: func(c *config.Config) { c.CheckIn, c.CheckOut = "08/05/2026", "2026-05-10" }, wantErr: true},
+		{name: "negative guests", set: func(c *config.Config) { c.Children = -1 }, wantErr: true},
+		{name: "min over max price", set: func(c *config.Config) { c.MinPrice, c.MaxPrice = 300, 200 }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.BaseURL = "https://www.airbnb.com/"
+			cfg.SearchLocation = "Kuala Lumpur, Malaysia"
+			tt.set(cfg)
+
+			got, err := BuildSearchURL(cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("BuildSearchURL = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildSearchURL: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("BuildSearchURL =\n %s\nwant\n %s", got, tt.want)
+			}
+		})
+	}
+}
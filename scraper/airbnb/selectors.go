@@ -0,0 +1,146 @@
+package airbnb
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed selectors/default.yaml
+var defaultSelectors []byte
+
+// SelectorSet is a versioned set of per-field extractor chains, loaded
+// from a YAML selector file (see selectors/default.yaml for the format).
+type SelectorSet struct {
+	Version string
+	Source  string // file path, or "embedded"
+	chains  []chain
+}
+
+type selectorFile struct {
+	Version string                    `yaml:"version"`
+	Fields  map[string][]strategySpec `yaml:"fields"`
+}
+
+type strategySpec struct {
+	Name     string   `yaml:"name"`
+	Type     string   `yaml:"type"`
+	Path     string   `yaml:"path"`
+	Paths    []string `yaml:"paths"`
+	Join     string   `yaml:"join"`
+	Field    string   `yaml:"field"`
+	Selector string   `yaml:"selector"`
+	Attr     string   `yaml:"attr"`
+	Script   string   `yaml:"script"`
+	Pattern  string   `yaml:"pattern"`
+}
+
+// LoadSelectorSet reads the selector file at path, or the embedded
+// default set when path is empty.
+func LoadSelectorSet(path string) (*SelectorSet, error) {
+	data, source := defaultSelectors, "embedded"
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read selector file: %w", err)
+		}
+		data, source = b, path
+	}
+
+	set, err := parseSelectorSet(data)
+	if err != nil {
+		return nil, fmt.Errorf("selector file %s: %w", source, err)
+	}
+	set.Source = source
+	return set, nil
+}
+
+func parseSelectorSet(data []byte) (*SelectorSet, error) {
+	var file selectorFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(file.Version) == "" {
+		return nil, fmt.Errorf("missing version")
+	}
+
+	set := &SelectorSet{Version: file.Version}
+
+	// Chains run in listingFields order so logs and Sources are stable.
+	for _, f := range listingFields {
+		specs, ok := file.Fields[f.name]
+		if !ok {
+			continue
+		}
+		c := chain{field: f.name}
+		for i, spec := range specs {
+			e, err := buildExtractor(spec)
+			if err != nil {
+				return nil, fmt.Errorf("%s[%d]: %w", f.name, i, err)
+			}
+			c.extractors = append(c.extractors, e)
+		}
+		set.chains = append(set.chains, c)
+	}
+
+	for name := range file.Fields {
+		if fieldByName(name) == nil {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+	}
+
+	return set, nil
+}
+
+func buildExtractor(spec strategySpec) (Extractor, error) {
+	if spec.Name == "" {
+		return nil, fmt.Errorf("strategy without a name")
+	}
+
+	var e Extractor
+	switch spec.Type {
+	case sourceJSONLD, sourceState, sourceAPI:
+		paths := spec.Paths
+		if spec.Path != "" {
+			paths = append([]string{spec.Path}, paths...)
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("%s: %s strategy needs path or paths", spec.Name, spec.Type)
+		}
+		e = &pathExtractor{name: spec.Name, source: spec.Type, paths: paths, join: spec.Join}
+
+	case "search":
+		if spec.Field == "" {
+			return nil, fmt.Errorf("%s: search strategy needs field", spec.Name)
+		}
+		e = &searchExtractor{name: spec.Name, field: spec.Field}
+
+	case "css":
+		if spec.Selector == "" {
+			return nil, fmt.Errorf("%s: css strategy needs selector", spec.Name)
+		}
+		e = newCSSExtractor(spec.Name, spec.Selector, spec.Attr)
+
+	case "script":
+		if strings.TrimSpace(spec.Script) == "" {
+			return nil, fmt.Errorf("%s: script strategy needs script", spec.Name)
+		}
+		e = &scriptExtractor{name: spec.Name, script: spec.Script}
+
+	default:
+		return nil, fmt.Errorf("%s: unknown strategy type %q", spec.Name, spec.Type)
+	}
+
+	if spec.Pattern != "" {
+		re, err := regexp.Compile(spec.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid pattern: %w", spec.Name, err)
+		}
+		e = &patternExtractor{Extractor: e, re: re}
+	}
+	return e, nil
+}
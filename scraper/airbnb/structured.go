@@ -0,0 +1,202 @@
+package airbnb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+)
+
+// Structured sources a selector strategy can read from (its `type`).
+const (
+	sourceJSONLD = "jsonld" // <script type="application/ld+json">
+	sourceState  = "state"  // Airbnb's embedded page state (<script type="application/json">)
+)
+
+// structuredData is the machine-readable data Airbnb embeds in every
+// detail page. Unlike the obfuscated CSS class names it does not change
+// with every frontend deploy, so the default selector set tries it
+// before the DOM strategies.
+type structuredData struct {
+	jsonLD []interface{}
+	state  []interface{}
+	api    []interface{} // captured StaysPdpSections bodies, see network.go
+}
+
+// collectStructuredData reads the JSON-LD blocks and the deferred-state /
+// injector blobs from the current page and parses them. Blobs that fail
+// to parse are skipped.
+func collectStructuredData(ctx context.Context) (*structuredData, error) {
+	var raw struct {
+		JSONLD []string `json:"jsonld"`
+		State  []string `json:"state"`
+	}
+
+	err := chromedp.Run(ctx, chromedp.Evaluate(`(() => ({
+		jsonld: Array.from(document.querySelectorAll('script[type="application/ld+json"]')).map(s => s.textContent || ''),
+		state: Array.from(document.querySelectorAll('script[type="application/json"]')).map(s => s.textContent || '')
+	}))()`, &raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded page data: %w", err)
+	}
+
+	return &structuredData{
+		jsonLD: parseBlobs(raw.JSONLD),
+		state:  parseBlobs(raw.State),
+	}, nil
+}
+
+func parseBlobs(blobs []string) []interface{} {
+	var out []interface{}
+	for _, b := range blobs {
+		b = strings.TrimSpace(b)
+		if b == "" {
+			continue
+		}
+		var v interface{}
+		if err := json.Unmarshal([]byte(b), &v); err != nil {
+			continue
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+func (d *structuredData) roots(source string) []interface{} {
+	switch source {
+	case sourceJSONLD:
+		return d.jsonLD
+	case sourceAPI:
+		return d.api
+	default:
+		return d.state
+	}
+}
+
+var pathFilter = regexp.MustCompile(`^([^\[]*)\[([^=\]]+)=([^\]]*)\]$`)
+
+// lookupPath walks root along a dotted path and returns the first match.
+//
+//	name              object key
+//	**                any depth (the next segment may match anywhere below)
+//	name[key=value]   object key, keeping only objects whose key equals value
+//
+// Arrays are searched element by element, so "sections.section.title"
+// also works when "sections" is a list.
+func lookupPath(root interface{}, path string) (interface{}, bool) {
+	return walkPath(root, strings.Split(path, "."))
+}
+
+func walkPath(node interface{}, segs []string) (interface{}, bool) {
+	if len(segs) == 0 {
+		if node == nil {
+			return nil, false
+		}
+		return node, true
+	}
+
+	if list, ok := node.([]interface{}); ok {
+		for _, item := range list {
+			if v, ok := walkPath(item, segs); ok {
+				return v, true
+			}
+		}
+		return nil, false
+	}
+
+	seg := segs[0]
+	if seg == "**" {
+		if v, ok := walkPath(node, segs[1:]); ok {
+			return v, true
+		}
+		if obj, ok := node.(map[string]interface{}); ok {
+			// Sorted so the same page always yields the same match.
+			keys := make([]string, 0, len(obj))
+			for k := range obj {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				if v, ok := walkPath(obj[k], segs); ok {
+					return v, true
+				}
+			}
+		}
+		return nil, false
+	}
+
+	obj, ok := node.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	key, filterKey, filterValue := seg, "", ""
+	if m := pathFilter.FindStringSubmatch(seg); m != nil {
+		key, filterKey, filterValue = m[1], m[2], m[3]
+	}
+
+	child, ok := obj[key]
+	if !ok {
+		return nil, false
+	}
+	if filterKey != "" {
+		child = filterObjects(child, filterKey, filterValue)
+		if child == nil {
+			return nil, false
+		}
+	}
+	return walkPath(child, segs[1:])
+}
+
+func filterObjects(node interface{}, key, value string) interface{} {
+	matches := func(v interface{}) bool {
+		obj, ok := v.(map[string]interface{})
+		return ok && scalarString(obj[key]) == value
+	}
+
+	if list, ok := node.([]interface{}); ok {
+		var kept []interface{}
+		for _, item := range list {
+			if matches(item) {
+				kept = append(kept, item)
+			}
+		}
+		if len(kept) == 0 {
+			return nil
+		}
+		return kept
+	}
+	if matches(node) {
+		return node
+	}
+	return nil
+}
+
+func scalarString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(t)
+	default:
+		return ""
+	}
+}
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// cleanStructured turns embedded HTML snippets (descriptions use <br />)
+// into plain single-spaced text.
+func cleanStructured(s string) string {
+	s = htmlTag.ReplaceAllString(s, " ")
+	s = html.UnescapeString(s)
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -4,58 +4,110 @@ import (
 	"airbnb-scraper/config"
 	"airbnb-scraper/models"
 	"airbnb-scraper/utils"
+	"context"
+	"errors"
 	"sync"
 )
 
 type WorkerPool struct {
-	scraper *Scraper
-	cfg     *config.Config
-	jobs    chan string
-	results chan models.ScrapeResult
-	wg      sync.WaitGroup
+	scraper    *Scraper
+	cfg        *config.Config
+	checkpoint *Checkpoint
+	jobs       chan string
+	results    chan models.ScrapeResult
+	wg         sync.WaitGroup
 }
 
-func NewWorkerPool(scraper *Scraper, cfg *config.Config) *WorkerPool {
+// NewWorkerPool creates a pool that records its progress in checkpoint.
+// A checkpoint loaded from an earlier run makes Run resume it.
+func NewWorkerPool(scraper *Scraper, cfg *config.Config, checkpoint *Checkpoint) *WorkerPool {
 	return &WorkerPool{
-		scraper: scraper,
-		cfg:     cfg,
+		scraper:    scraper,
+		cfg:        cfg,
+		checkpoint: checkpoint,
 	}
 }
 
-func (p *WorkerPool) Run() []models.Listing {
-	utils.Info("Collecting section URLs")
-
-	sectionURLs, err := p.scraper.GetSectionURLs()
-	if err != nil {
-		utils.Error("Failed to get section URLs: %v", err)
-		return nil
+// Run crawls the sections and scrapes their properties, or in cards-only
+// mode builds the listings from the section cards. When ctx is
+// cancelled it stops handing out sections and property pages, waits for
+// the pages already in flight and returns everything scraped so far.
+//
+// Sections already collected and properties already scraped according to
+// the checkpoint are not visited again; their listings are returned
+// alongside the new ones.
+func (p *WorkerPool) Run(ctx context.Context) []models.Listing {
+	sectionURLs := p.checkpoint.sectionURLs()
+	if len(sectionURLs) > 0 {
+		utils.Info("Resuming run %s: %d sections already discovered", p.checkpoint.RunID, len(sectionURLs))
+	} else {
+		var err error
+		sectionURLs, err = p.sectionURLs()
+		if err != nil {
+			utils.Error("Failed to get section URLs: %v", err)
+			return nil
+		}
+		p.saved(p.checkpoint.setSections(sectionURLs))
 	}
 
 	if len(sectionURLs) < p.cfg.MaxPages {
-		utils.Warn("Only %d sections found, need %d", len(sectionURLs), p.cfg.MaxPages)
+		if !p.cfg.SearchMode() {
+			utils.Warn("Only %d sections found, need %d", len(sectionURLs), p.cfg.MaxPages)
+		}
 		p.cfg.MaxPages = len(sectionURLs)
 	}
 
 	utils.Info("Processing up to %d sections", p.cfg.MaxPages)
+	if p.cfg.CardsOnly {
+		utils.Info("Cards-only mode: listings come from search results cards, detail pages are not opened")
+	}
 
 	var allListings []models.Listing
+	counted := make(map[string]bool)
+	add := func(listings []models.Listing) {
+		for _, l := range listings {
+			if !counted[l.Key()] {
+				counted[l.Key()] = true
+				allListings = append(allListings, l)
+			}
+		}
+	}
 
 	for pageNum := 1; pageNum <= p.cfg.MaxPages; pageNum++ {
+		if ctx.Err() != nil {
+			utils.Warn("Shutting down; skipping sections %d-%d", pageNum, p.cfg.MaxPages)
+			break
+		}
 		sectionURL := sectionURLs[pageNum-1]
 
-		propertyURLs, err := p.scraper.GetPropertyURLsFromSection(sectionURL)
-		if err != nil {
-			utils.Error("Page %d failed: %v", pageNum, err)
+		if p.cfg.CardsOnly {
+			add(p.sectionCards(ctx, pageNum, sectionURL))
 			continue
 		}
 
-		if len(propertyURLs) == 0 {
+		propertyURLs, collected := p.checkpoint.sectionProperties(pageNum - 1)
+		if !collected {
+			var err error
+			propertyURLs, err = p.scraper.GetPropertyURLsFromSection(ctx, sectionURL)
+			if err != nil {
+				utils.Error("Page %d failed: %v", pageNum, err)
+				continue
+			}
+			p.saved(p.checkpoint.setSectionProperties(pageNum-1, propertyURLs))
+		}
+
+		done, todo := p.checkpoint.split(propertyURLs)
+		add(done)
+		if len(done) > 0 {
+			utils.Info("Section %d: %d properties restored from checkpoint, %d to scrape", pageNum, len(done), len(todo))
+		}
+
+		if len(todo) == 0 {
 			continue
 		}
 
 		utils.Info("Scraping property details for section %d", pageNum)
-		sectionListings := p.scrapeProperties(propertyURLs)
-		allListings = append(allListings, sectionListings...)
+		add(p.scrapeProperties(ctx, todo))
 	}
 
 	if len(allListings) == 0 {
@@ -67,8 +119,48 @@ func (p *WorkerPool) Run() []models.Listing {
 	return allListings
 }
 
-func (p *WorkerPool) scrapeProperties(propertyURLs []string) []models.Listing {
-	p.jobs = make(chan string, len(propertyURLs))
+// sectionCards builds the listings of section pageNum from its search
+// results cards. A section whose listings are all in the checkpoint is
+// restored from it; otherwise its cards are read again.
+func (p *WorkerPool) sectionCards(ctx context.Context, pageNum int, sectionURL string) []models.Listing {
+	if urls, collected := p.checkpoint.sectionProperties(pageNum - 1); collected {
+		done, todo := p.checkpoint.split(urls)
+		if len(todo) == 0 {
+			utils.Info("Section %d: %d listings restored from checkpoint", pageNum, len(done))
+			return done
+		}
+	}
+
+	listings, err := p.scraper.GetListingsFromSection(ctx, sectionURL)
+	if err != nil {
+		utils.Error("Page %d failed: %v", pageNum, err)
+		return nil
+	}
+	p.saved(p.checkpoint.setSectionListings(pageNum-1, listings))
+	return listings
+}
+
+// sectionURLs returns the search pages to crawl: the single search URL
+// built from the config in search mode, otherwise whatever destination
+// links the homepage currently shows.
+func (p *WorkerPool) sectionURLs() ([]string, error) {
+	if p.cfg.SearchMode() {
+		searchURL, err := BuildSearchURL(p.cfg)
+		if err != nil {
+			return nil, err
+		}
+		utils.Info("Search mode: %s", searchURL)
+		return []string{searchURL}, nil
+	}
+
+	utils.Info("Collecting section URLs")
+	return p.scraper.GetSectionURLs()
+}
+
+func (p *WorkerPool) scrapeProperties(ctx context.Context, propertyURLs []string) []models.Listing {
+	// Unbuffered, so a job is only handed out when a worker is free and
+	// dispatch can stop as soon as ctx is cancelled.
+	p.jobs = make(chan string)
 	p.results = make(chan models.ScrapeResult, len(propertyURLs))
 
 	workerCount := p.cfg.MaxWorkers
@@ -78,13 +170,20 @@ func (p *WorkerPool) scrapeProperties(propertyURLs []string) []models.Listing {
 
 	p.wg.Add(workerCount)
 	for i := 1; i <= workerCount; i++ {
-		go p.worker(i)
+		go p.worker(ctx, i)
 	}
 
-	for _, url := range propertyURLs {
-		p.jobs <- url
-	}
-	close(p.jobs)
+	go func() {
+		defer close(p.jobs)
+		for i, url := range propertyURLs {
+			select {
+			case p.jobs <- url:
+			case <-ctx.Done():
+				utils.Warn("Shutting down; %d property pages not started", len(propertyURLs)-i)
+				return
+			}
+		}
+	}()
 
 	go func() {
 		p.wg.Wait()
@@ -94,11 +193,12 @@ func (p *WorkerPool) scrapeProperties(propertyURLs []string) []models.Listing {
 	return p.collect()
 }
 
-func (p *WorkerPool) worker(id int) {
+func (p *WorkerPool) worker(ctx context.Context, id int) {
 	defer p.wg.Done()
 
 	for propertyURL := range p.jobs {
-		listing, err := p.scraper.ScrapePropertyPage(propertyURL)
+		listing, err := p.scraper.ScrapePropertyPage(ctx, propertyURL)
+		p.record(propertyURL, listing, err)
 
 		p.results <- models.ScrapeResult{
 			Listings: []models.Listing{listing},
@@ -107,11 +207,36 @@ func (p *WorkerPool) worker(id int) {
 	}
 }
 
+// record stores the outcome of one property in the checkpoint. Pages
+// skipped on shutdown stay pending, and an empty listing without error
+// means another worker already had the URL.
+func (p *WorkerPool) record(propertyURL string, listing models.Listing, err error) {
+	switch {
+	case errors.Is(err, context.Canceled):
+	case err != nil:
+		p.saved(p.checkpoint.markFailed(propertyURL, err))
+	case listing.URL != "":
+		p.saved(p.checkpoint.markDone(propertyURL, listing))
+	}
+}
+
+// saved warns about a failed checkpoint write; losing the checkpoint only
+// costs resumability, so the run carries on.
+func (p *WorkerPool) saved(err error) {
+	if err != nil {
+		utils.Warn("Checkpoint not saved: %v", err)
+	}
+}
+
 func (p *WorkerPool) collect() []models.Listing {
 	var all []models.Listing
-	failed := 0
+	failed, skipped := 0, 0
 
 	for result := range p.results {
+		if errors.Is(result.Error, context.Canceled) {
+			skipped++
+			continue
+		}
 		if result.Error != nil {
 			utils.Error("Property failed: %v", result.Error)
 			failed++
@@ -122,6 +247,10 @@ func (p *WorkerPool) collect() []models.Listing {
 		}
 	}
 
-	utils.Success("Properties scraped: %d | Failed: %d", len(all), failed)
+	if skipped > 0 {
+		utils.Success("Properties scraped: %d | Failed: %d | Skipped on shutdown: %d", len(all), failed, skipped)
+	} else {
+		utils.Success("Properties scraped: %d | Failed: %d", len(all), failed)
+	}
 	return all
 }
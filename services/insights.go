@@ -8,9 +8,13 @@ import (
 	"strings"
 )
 
+// Report prices are per night (Listing.PricePerNight), so totals for a
+// stay and nightly rates are not averaged together; listings whose price
+// could not be normalized are left out of every price figure.
 type Report struct {
 	TotalListings       int
 	AirbnbListings      int
+	PricedListings      int // listings with a price per night
 	AveragePrice        float64
 	MinPrice            float64
 	MaxPrice            float64
@@ -18,8 +22,104 @@ type Report struct {
 	TopRated            []models.Listing
 	ListingsByLocation  map[string]int
 	CleanedListingCount int
+
+	// ByBedrooms compares prices between listings of the same size, one
+	// row per bedroom count (0 is a studio, 4 means 4 or more), so a
+	// studio is not averaged together with a 4-bedroom house. Only
+	// listings with a price and a known capacity are counted.
+	ByBedrooms []BedroomPrices
+
+	// AmenityPremiums compares the average price of listings with and
+	// without each amenity, largest premium first. Only listings with a
+	// price and a scraped amenities list count, and an amenity needs at
+	// least minAmenityGroup listings on each side.
+	AmenityPremiums []AmenityPremium
+
+	// ByHostType compares individual hosts with professional ones
+	// (property managers, companies). Listings without host data are
+	// left out.
+	ByHostType []HostTypePrices
+
+	// ByCancellation compares prices across cancellation policies, from
+	// the most lenient to the strictest. Listings without a price or a
+	// known policy are left out.
+	ByCancellation []CancellationPrices
+
+	// Occupancy is estimated from availability calendars (see Occupancy):
+	// OccupancyByLocation averages it per location, BusiestListings are the
+	// five listings with the highest estimate. Listings without a calendar
+	// are left out.
+	OccupancyListings   int
+	AverageOccupancy    float64 // 0-1
+	OccupancyByLocation []LocationOccupancy
+	BusiestListings     []ListingOccupancy
+}
+
+// LocationOccupancy is one row of Report.OccupancyByLocation.
+// AveragePrice is over the listings that also have a price.
+type LocationOccupancy struct {
+	Location     string
+	Listings     int
+	Occupancy    float64 // 0-1
+	AveragePrice float64
+}
+
+// ListingOccupancy is one row of Report.BusiestListings. Days is the
+// length of the booking window the estimate covers.
+type ListingOccupancy struct {
+	Listing   models.Listing
+	Occupancy float64 // 0-1
+	Days      int
+}
+
+// HostTypePrices is one row of Report.ByHostType.
+type HostTypePrices struct {
+	HostType        string // "Individual" or "Professional"
+	Hosts           int
+	Listings        int
+	AveragePrice    float64
+	AveragePerGuest float64 // over listings with a known guest count
+	Superhosts      int
+}
+
+// CancellationPrices is one row of Report.ByCancellation. AverageRating
+// is over the listings that have a rating.
+type CancellationPrices struct {
+	Policy        string // one of models.CancellationTiers
+	Listings      int
+	AveragePrice  float64
+	MinPrice      float64
+	MaxPrice      float64
+	AverageRating float64
+}
+
+// AmenityPremium is one row of Report.AmenityPremiums. Premium is the
+// difference in average price, in percent of the price without it.
+type AmenityPremium struct {
+	Amenity        string
+	With           int
+	Without        int
+	AverageWith    float64
+	AverageWithout float64
+	Premium        float64
 }
 
+const (
+	minAmenityGroup    = 2
+	maxAmenityPremiums = 10
+)
+
+// BedroomPrices is one row of Report.ByBedrooms.
+type BedroomPrices struct {
+	Bedrooms        int
+	Listings        int
+	AveragePrice    float64
+	AveragePerGuest float64
+}
+
+// maxBedroomBand is the last ByBedrooms row; larger listings fall into it.
+const maxBedroomBand = 4
+
 // GenerateReport cleans the dataset and computes all assignment insights.
 func GenerateReport(listings []models.Listing) Report {
 	cleaned := CleanListings(listings)
@@ -53,19 +153,18 @@ func GenerateReport(listings []models.Listing) Report {
 			report.AirbnbListings++
 		}
 
-		location := normalizeLocation(l.Location)
-		report.ListingsByLocation[location]++
+		report.ListingsByLocation[placeOf(l)]++
 
-		if l.Price > 0 {
-			priceSum += l.Price
+		if l.PricePerNight > 0 {
+			priceSum += l.PricePerNight
 			priceCount++
 
-			if l.Price > maxPrice {
-				maxPrice = l.Price
+			if l.PricePerNight > maxPrice {
+				maxPrice = l.PricePerNight
 				report.MostExpensive = l
 			}
-			if l.Price < minPrice {
-				minPrice = l.Price
+			if l.PricePerNight < minPrice {
+				minPrice = l.PricePerNight
 			}
 		}
 
@@ -75,26 +174,312 @@ func GenerateReport(listings []models.Listing) Report {
 	}
 
 	if priceCount > 0 {
+		report.PricedListings = priceCount
 		report.AveragePrice = priceSum / float64(priceCount)
 		report.MinPrice = minPrice
 		report.MaxPrice = maxPrice
 	}
 
-	sort.Slice(highestRated, func(i, j int) bool {
-		if highestRated[i].Rating == highestRated[j].Rating {
-			return highestRated[i].Price > highestRated[j].Price
+	score := weightedRatings(highestRated)
+	sort.SliceStable(highestRated, func(i, j int) bool {
+		si, sj := score(highestRated[i]), score(highestRated[j])
+		if si == sj {
+			return highestRated[i].PricePerNight > highestRated[j].PricePerNight
 		}
-		return highestRated[i].Rating > highestRated[j].Rating
+		return si > sj
 	})
 
 	if len(highestRated) > 5 {
 		highestRated = highestRated[:5]
 	}
 	report.TopRated = highestRated
+	report.ByBedrooms = pricesByBedrooms(cleaned)
+	report.AmenityPremiums = amenityPremiums(cleaned)
+	report.ByHostType = pricesByHostType(cleaned)
+	report.ByCancellation = pricesByCancellation(cleaned)
+	occupancyReport(&report, cleaned)
 
 	return report
 }
 
+func pricesByBedrooms(listings []models.Listing) []BedroomPrices {
+	bands := make(map[int]*BedroomPrices)
+	for _, l := range listings {
+		if l.PricePerNight <= 0 || l.Guests <= 0 {
+			continue
+		}
+		n := min(l.Bedrooms, maxBedroomBand)
+		b, ok := bands[n]
+		if !ok {
+			b = &BedroomPrices{Bedrooms: n}
+			bands[n] = b
+		}
+		b.Listings++
+		b.AveragePrice += l.PricePerNight
+		b.AveragePerGuest += l.PricePerNight / float64(l.Guests)
+	}
+
+	out := make([]BedroomPrices, 0, len(bands))
+	for _, b := range bands {
+		b.AveragePrice /= float64(b.Listings)
+		b.AveragePerGuest /= float64(b.Listings)
+		out = append(out, *b)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Bedrooms < out[j].Bedrooms })
+	return out
+}
+
+// ratingPriorReviews is how many "average" reviews every listing is
+// assumed to have before its own count, see weightedRatings.
+const ratingPriorReviews = 10
+
+// weightedRatings returns the ranking score for TopRated: a Bayesian
+// average that pulls ratings with few reviews towards the mean rating, so
+// 5.0 from 2 reviews ranks below 4.9 from 800. When no listing has a
+// review count (older data), the plain rating is used.
+func weightedRatings(rated []models.Listing) func(models.Listing) float64 {
+	var sum float64
+	haveCounts := false
+	for _, l := range rated {
+		sum += l.Rating
+		if l.ReviewCount > 0 {
+			haveCounts = true
+		}
+	}
+	if !haveCounts || len(rated) == 0 {
+		return func(l models.Listing) float64 { return l.Rating }
+	}
+
+	mean := sum / float64(len(rated))
+	return func(l models.Listing) float64 {
+		v, m := float64(l.ReviewCount), float64(ratingPriorReviews)
+		return (v*l.Rating + m*mean) / (v + m)
+	}
+}
+
+func amenityPremiums(listings []models.Listing) []AmenityPremium {
+	var priced []models.Listing
+	var names []string
+	seen := make(map[string]bool)
+	for _, l := range listings {
+		if l.PricePerNight <= 0 || len(l.Amenities) == 0 {
+			continue
+		}
+		priced = append(priced, l)
+		for _, a := range l.Amenities {
+			if key := strings.ToLower(a.Name); a.Available && !seen[key] {
+				seen[key] = true
+				names = append(names, a.Name)
+			}
+		}
+	}
+
+	var out []AmenityPremium
+	for _, name := range names {
+		p := AmenityPremium{Amenity: name}
+		for _, l := range priced {
+			if l.HasAmenity(name) {
+				p.With++
+				p.AverageWith += l.PricePerNight
+			} else {
+				p.Without++
+				p.AverageWithout += l.PricePerNight
+			}
+		}
+		if p.With < minAmenityGroup || p.Without < minAmenityGroup {
+			continue
+		}
+		p.AverageWith /= float64(p.With)
+		p.AverageWithout /= float64(p.Without)
+		p.Premium = (p.AverageWith - p.AverageWithout) / p.AverageWithout * 100
+		out = append(out, p)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Premium > out[j].Premium })
+	if len(out) > maxAmenityPremiums {
+		out = out[:maxAmenityPremiums]
+	}
+	return out
+}
+
+func pricesByHostType(listings []models.Listing) []HostTypePrices {
+	rows := []HostTypePrices{{HostType: "Individual"}, {HostType: "Professional"}}
+	hosts := make([]map[int64]bool, len(rows))
+	perGuest := make([]int, len(rows))
+	for i := range hosts {
+		hosts[i] = make(map[int64]bool)
+	}
+
+	for _, l := range listings {
+		if l.Host.ID == 0 || l.PricePerNight <= 0 {
+			continue
+		}
+		i := 0
+		if l.Host.Professional {
+			i = 1
+		}
+		r := &rows[i]
+		r.Listings++
+		r.AveragePrice += l.PricePerNight
+		if l.Guests > 0 {
+			r.AveragePerGuest += l.PricePerNight / float64(l.Guests)
+			perGuest[i]++
+		}
+		if !hosts[i][l.Host.ID] {
+			hosts[i][l.Host.ID] = true
+			r.Hosts++
+			if l.Host.Superhost {
+				r.Superhosts++
+			}
+		}
+	}
+
+	var out []HostTypePrices
+	for i, r := range rows {
+		if r.Listings == 0 {
+			continue
+		}
+		r.AveragePrice /= float64(r.Listings)
+		if perGuest[i] > 0 {
+			r.AveragePerGuest /= float64(perGuest[i])
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func pricesByCancellation(listings []models.Listing) []CancellationPrices {
+	rows := make([]CancellationPrices, len(models.CancellationTiers))
+	rated := make([]int, len(rows))
+	for i, tier := range models.CancellationTiers {
+		rows[i] = CancellationPrices{Policy: tier, MinPrice: math.MaxFloat64}
+	}
+
+	for _, l := range listings {
+		i := indexOf(models.CancellationTiers, l.Policies.Cancellation)
+		if i < 0 || l.PricePerNight <= 0 {
+			continue
+		}
+		r := &rows[i]
+		r.Listings++
+		r.AveragePrice += l.PricePerNight
+		r.MinPrice = math.Min(r.MinPrice, l.PricePerNight)
+		r.MaxPrice = math.Max(r.MaxPrice, l.PricePerNight)
+		if l.Rating > 0 {
+			r.AverageRating += l.Rating
+			rated[i]++
+		}
+	}
+
+	var out []CancellationPrices
+	for i, r := range rows {
+		if r.Listings == 0 {
+			continue
+		}
+		r.AveragePrice /= float64(r.Listings)
+		if rated[i] > 0 {
+			r.AverageRating /= float64(rated[i])
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func indexOf(values []string, v string) int {
+	for i, s := range values {
+		if s == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// Occupancy estimates the share of nights a listing is booked from its
+// availability calendar: blocked days over the days of its booking
+// window. Airbnb shows nights the host closed like booked ones, so this
+// is an upper bound. The window ends at the last available day, since
+// hosts who take bookings only a few months ahead have every later day
+// blocked. A calendar without a single available day means the listing
+// is not taking bookings; it has no estimate (ok is false), and neither
+// has a listing without a calendar. days is the window's length.
+func Occupancy(calendar []models.CalendarDay) (rate float64, days int, ok bool) {
+	last := -1
+	for i, d := range calendar {
+		if d.Available {
+			last = i
+		}
+	}
+	if last < 0 {
+		return 0, 0, false
+	}
+
+	blocked := 0
+	for _, d := range calendar[:last+1] {
+		if !d.Available {
+			blocked++
+		}
+	}
+	return float64(blocked) / float64(last+1), last + 1, true
+}
+
+// maxBusiestListings is the length of Report.BusiestListings.
+const maxBusiestListings = 5
+
+func occupancyReport(report *Report, listings []models.Listing) {
+	type group struct {
+		row    LocationOccupancy
+		priced int
+	}
+	groups := make(map[string]*group)
+	var sum float64
+	var busiest []ListingOccupancy
+
+	for _, l := range listings {
+		rate, days, ok := Occupancy(l.Calendar)
+		if !ok {
+			continue
+		}
+		sum += rate
+		busiest = append(busiest, ListingOccupancy{Listing: l, Occupancy: rate, Days: days})
+
+		place := placeOf(l)
+		g, ok := groups[place]
+		if !ok {
+			g = &group{row: LocationOccupancy{Location: place}}
+			groups[place] = g
+		}
+		g.row.Listings++
+		g.row.Occupancy += rate
+		if l.PricePerNight > 0 {
+			g.row.AveragePrice += l.PricePerNight
+			g.priced++
+		}
+	}
+	if len(busiest) == 0 {
+		return
+	}
+
+	report.OccupancyListings = len(busiest)
+	report.AverageOccupancy = sum / float64(len(busiest))
+
+	for _, g := range groups {
+		g.row.Occupancy /= float64(g.row.Listings)
+		if g.priced > 0 {
+			g.row.AveragePrice /= float64(g.priced)
+		}
+		report.OccupancyByLocation = append(report.OccupancyByLocation, g.row)
+	}
+	sort.Slice(report.OccupancyByLocation, func(i, j int) bool {
+		return report.OccupancyByLocation[i].Location < report.OccupancyByLocation[j].Location
+	})
+
+	sort.SliceStable(busiest, func(i, j int) bool { return busiest[i].Occupancy > busiest[j].Occupancy })
+	if len(busiest) > maxBusiestListings {
+		busiest = busiest[:maxBusiestListings]
+	}
+	report.BusiestListings = busiest
+}
+
 func PrintReport(report Report) {
 	fmt.Println()
 	fmt.Println("┌──────────────────────────────────────────────────────────────┐")
@@ -102,9 +487,10 @@ func PrintReport(report Report) {
 	fmt.Println("├───────────────────────────────┬──────────────────────────────┤")
 	fmt.Printf("│ %-29s │ %-28d │\n", "Total Listings Scraped", report.TotalListings)
 	fmt.Printf("│ %-29s │ %-28d │\n", "Airbnb Listings", report.AirbnbListings)
-	fmt.Printf("│ %-29s │ %-28.2f │\n", "Average Price", report.AveragePrice)
-	fmt.Printf("│ %-29s │ %-28.2f │\n", "Minimum Price", report.MinPrice)
-	fmt.Printf("│ %-29s │ %-28.2f │\n", "Maximum Price", report.MaxPrice)
+	fmt.Printf("│ %-29s │ %-28d │\n", "Listings Priced per Night", report.PricedListings)
+	fmt.Printf("│ %-29s │ %-28.2f │\n", "Average Price per Night", report.AveragePrice)
+	fmt.Printf("│ %-29s │ %-28.2f │\n", "Minimum Price per Night", report.MinPrice)
+	fmt.Printf("│ %-29s │ %-28.2f │\n", "Maximum Price per Night", report.MaxPrice)
 	fmt.Println("└───────────────────────────────┴──────────────────────────────┘")
 
 	if report.MostExpensive.Title != "" {
@@ -112,8 +498,9 @@ func PrintReport(report Report) {
 		fmt.Println("┌──────────────────────────────────────────────────────────────┐")
 		fmt.Println("│                    Most Expensive Property                   │")
 		fmt.Println("├───────────────────────────────┬──────────────────────────────┤")
-		fmt.Printf("│ %-29s │ %-28.2f │\n", "Price", report.MostExpensive.Price)
+		fmt.Printf("│ %-29s │ %-28.2f │\n", "Price per Night", report.MostExpensive.PricePerNight)
 		fmt.Printf("│ %-29s │ %-28s │\n", "Location", normalizeLocation(report.MostExpensive.Location))
+		fmt.Printf("│ %-29s │ %-28s │\n", "Size", formatCapacity(report.MostExpensive))
 		fmt.Println("└───────────────────────────────┴──────────────────────────────┘")
 		fmt.Printf("Title: %s\n", report.MostExpensive.Title)
 	}
@@ -129,13 +516,79 @@ func PrintReport(report Report) {
 	fmt.Println("└──────────────────────────────────────────────┴───────────────┘")
 
 	fmt.Println()
-	fmt.Println("┌─────┬──────────────────────────────────────────────┬──────────┐")
-	fmt.Println("│ #   │ Top 5 Highest Rated Properties               │ Rating   │")
-	fmt.Println("├─────┼──────────────────────────────────────────────┼──────────┤")
+	fmt.Println("┌─────┬──────────────────────────────────────────────┬──────────┬──────────┐")
+	fmt.Println("│ #   │ Top 5 Highest Rated Properties               │ Rating   │ Reviews  │")
+	fmt.Println("├─────┼──────────────────────────────────────────────┼──────────┼──────────┤")
 	for i, l := range report.TopRated {
-		fmt.Printf("│ %-3d │ %-44s │ %-8.2f │\n", i+1, truncateText(l.Title, 44), l.Rating)
+		fmt.Printf("│ %-3d │ %-44s │ %-8.2f │ %-8s │\n", i+1, truncateText(l.Title, 44), l.Rating, formatReviewCount(l.ReviewCount))
+	}
+	fmt.Println("└─────┴──────────────────────────────────────────────┴──────────┴──────────┘")
+
+	if len(report.ByBedrooms) > 0 {
+		fmt.Println()
+		fmt.Println("┌──────────────┬──────────┬──────────────────┬──────────────────┐")
+		fmt.Println("│ Bedrooms     │ Listings │ Average Price    │ Price per Guest  │")
+		fmt.Println("├──────────────┼──────────┼──────────────────┼──────────────────┤")
+		for _, b := range report.ByBedrooms {
+			fmt.Printf("│ %-12s │ %-8d │ %-16.2f │ %-16.2f │\n", bedroomLabel(b.Bedrooms), b.Listings, b.AveragePrice, b.AveragePerGuest)
+		}
+		fmt.Println("└──────────────┴──────────┴──────────────────┴──────────────────┘")
+	}
+
+	if len(report.AmenityPremiums) > 0 {
+		fmt.Println()
+		fmt.Println("┌────────────────────────────────┬────────┬───────────┬───────────┬──────────┐")
+		fmt.Println("│ Amenity Price Premium          │ With   │ Avg With  │ Avg w/o   │ Premium  │")
+		fmt.Println("├────────────────────────────────┼────────┼───────────┼───────────┼──────────┤")
+		for _, p := range report.AmenityPremiums {
+			fmt.Printf("│ %-30s │ %-6d │ %-9.2f │ %-9.2f │ %+7.1f%% │\n", truncateText(p.Amenity, 30), p.With, p.AverageWith, p.AverageWithout, p.Premium)
+		}
+		fmt.Println("└────────────────────────────────┴────────┴───────────┴───────────┴──────────┘")
+	}
+
+	if len(report.ByHostType) > 0 {
+		fmt.Println()
+		fmt.Println("┌──────────────┬────────┬──────────┬────────────┬───────────────┬─────────────────┐")
+		fmt.Println("│ Host Type    │ Hosts  │ Listings │ Superhosts │ Average Price │ Price per Guest │")
+		fmt.Println("├──────────────┼────────┼──────────┼────────────┼───────────────┼─────────────────┤")
+		for _, h := range report.ByHostType {
+			fmt.Printf("│ %-12s │ %-6d │ %-8d │ %-10d │ %-13.2f │ %-15.2f │\n", h.HostType, h.Hosts, h.Listings, h.Superhosts, h.AveragePrice, h.AveragePerGuest)
+		}
+		fmt.Println("└──────────────┴────────┴──────────┴────────────┴───────────────┴─────────────────┘")
+	}
+
+	if len(report.ByCancellation) > 0 {
+		fmt.Println()
+		fmt.Println("┌─────────────────────┬──────────┬───────────────┬───────────┬───────────┬──────────┐")
+		fmt.Println("│ Cancellation Policy │ Listings │ Average Price │ Min Price │ Max Price │ Rating   │")
+		fmt.Println("├─────────────────────┼──────────┼───────────────┼───────────┼───────────┼──────────┤")
+		for _, c := range report.ByCancellation {
+			fmt.Printf("│ %-19s │ %-8d │ %-13.2f │ %-9.2f │ %-9.2f │ %-8.2f │\n", cancellationLabel(c.Policy), c.Listings, c.AveragePrice, c.MinPrice, c.MaxPrice, c.AverageRating)
+		}
+		fmt.Println("└─────────────────────┴──────────┴───────────────┴───────────┴───────────┴──────────┘")
+	}
+
+	if report.OccupancyListings > 0 {
+		fmt.Println()
+		fmt.Println("┌──────────────────────────────────────────────┬──────────┬───────────┬───────────────┐")
+		fmt.Println("│ Estimated Occupancy per Location             │ Listings │ Occupancy │ Average Price │")
+		fmt.Println("├──────────────────────────────────────────────┼──────────┼───────────┼───────────────┤")
+		for _, o := range report.OccupancyByLocation {
+			fmt.Printf("│ %-44s │ %-8d │ %8.1f%% │ %-13.2f │\n", truncateText(o.Location, 44), o.Listings, o.Occupancy*100, o.AveragePrice)
+		}
+		fmt.Println("├──────────────────────────────────────────────┼──────────┼───────────┼───────────────┤")
+		fmt.Printf("│ %-44s │ %-8d │ %8.1f%% │ %-13s │\n", "All locations", report.OccupancyListings, report.AverageOccupancy*100, "")
+		fmt.Println("└──────────────────────────────────────────────┴──────────┴───────────┴───────────────┘")
+
+		fmt.Println()
+		fmt.Println("┌─────┬──────────────────────────────────────────────┬───────────┬──────────┐")
+		fmt.Println("│ #   │ Highest Estimated Occupancy                  │ Occupancy │ Days     │")
+		fmt.Println("├─────┼──────────────────────────────────────────────┼───────────┼──────────┤")
+		for i, o := range report.BusiestListings {
+			fmt.Printf("│ %-3d │ %-44s │ %8.1f%% │ %-8d │\n", i+1, truncateText(o.Listing.Title, 44), o.Occupancy*100, o.Days)
+		}
+		fmt.Println("└─────┴──────────────────────────────────────────────┴───────────┴──────────┘")
 	}
-	fmt.Println("└─────┴──────────────────────────────────────────────┴──────────┘")
 }
 
 func CleanListings(listings []models.Listing) []models.Listing {
@@ -152,11 +605,13 @@ func CleanListings(listings []models.Listing) []models.Listing {
 			continue
 		}
 
-		if seen[l.URL] {
+		// The same room can be reached through different URLs; the room
+		// ID is its identity.
+		if seen[l.Key()] {
 			continue
 		}
 
-		seen[l.URL] = true
+		seen[l.Key()] = true
 		cleaned = append(cleaned, l)
 	}
 
@@ -171,6 +626,21 @@ func normalizeLocation(location string) string {
 	return location
 }
 
+// placeOf is the ListingsByLocation bucket of a listing: "City, Country"
+// when the parts are known, so listings whose Location names the place
+// differently ("Kuala Lumpur, Malaysia" and "Bukit Bintang, Kuala Lumpur,
+// Malaysia") count together; otherwise the free-text Location.
+func placeOf(l models.Listing) string {
+	city, country := strings.TrimSpace(l.City), strings.TrimSpace(l.Country)
+	switch {
+	case city != "" && country != "":
+		return city + ", " + country
+	case city != "":
+		return city
+	}
+	return normalizeLocation(l.Location)
+}
+
 func sortedLocations(m map[string]int) []string {
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -180,6 +650,48 @@ func sortedLocations(m map[string]int) []string {
 	return keys
 }
 
+func formatReviewCount(n int) string {
+	if n == 0 {
+		return "-"
+	}
+	return fmt.Sprint(n)
+}
+
+func bedroomLabel(n int) string {
+	switch {
+	case n == 0:
+		return "Studio"
+	case n >= maxBedroomBand:
+		return fmt.Sprintf("%d+", maxBedroomBand)
+	}
+	return fmt.Sprint(n)
+}
+
+// cancellationLabel names a cancellation tier for the report.
+func cancellationLabel(tier string) string {
+	if tier == models.CancellationNonRefundable {
+		return "Non-refundable"
+	}
+	return strings.ToUpper(tier[:1]) + tier[1:]
+}
+
+// formatCapacity renders a listing's size as "4 guests · 2 bd · 1.5 ba",
+// or "-" when it is unknown.
+func formatCapacity(l models.Listing) string {
+	if l.Guests == 0 {
+		return "-"
+	}
+	bedrooms := "studio"
+	if l.Bedrooms > 0 {
+		bedrooms = fmt.Sprintf("%d bd", l.Bedrooms)
+	}
+	s := fmt.Sprintf("%d guests · %s", l.Guests, bedrooms)
+	if l.Bathrooms > 0 {
+		s += fmt.Sprintf(" · %g ba", l.Bathrooms)
+	}
+	return s
+}
+
 func truncateText(s string, max int) string {
 	if len(s) <= max {
 		return s
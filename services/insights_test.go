@@ -0,0 +1,65 @@
+package services
+
+import (
+	"airbnb-scraper/models"
+	"testing"
+)
+
+// calendar builds one day per character: 'a' available, 'b' blocked.
+func calendar(pattern string) []models.CalendarDay {
+	days := make([]models.CalendarDay, len(pattern))
+	for i, c := range pattern {
+		days[i] = models.CalendarDay{Available: c == 'a'}
+	}
+	return days
+}
+
+func TestOccupancy(t *testing.T) {
+	tests := []struct {
+		name     string
+		calendar []models.CalendarDay
+		wantRate float64
+		wantDays int
+		wantOK   bool
+	}{
+		{"all available", calendar("aaaa"), 0, 4, true},
+		{"half booked", calendar("baba"), 0.5, 4, true},
+		{"window ends at the last available day", calendar("bbabbbbb"), 2.0 / 3, 3, true},
+		{"only the last day open", calendar("bbba"), 0.75, 4, true},
+		{"nothing available", calendar("bbbb"), 0, 0, false},
+		{"no calendar", nil, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, days, ok := Occupancy(tt.calendar)
+			if rate != tt.wantRate || days != tt.wantDays || ok != tt.wantOK {
+				t.Errorf("Occupancy = %v, %d, %v; want %v, %d, %v", rate, days, ok, tt.wantRate, tt.wantDays, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestReportPricesPerNight(t *testing.T) {
+	listings := []models.Listing{
+		{ID: 1, Title: "Nightly", URL: "https://www.airbnb.com/rooms/1", Price: 100, PricePerNight: 100},
+		{ID: 2, Title: "Stay total", URL: "https://www.airbnb.com/rooms/2", Price: 600, TotalPrice: 600, Nights: 3, PricePerNight: 200},
+		// The unit of these headline prices is unknown: a stay total
+		// shown without its nights, and a row from an older version.
+		{ID: 3, Title: "Unknown unit", URL: "https://www.airbnb.com/rooms/3", Price: 900},
+		{ID: 4, Title: "Legacy", URL: "https://www.airbnb.com/rooms/4", Price: 50, RawPrice: "$50"},
+	}
+
+	for _, l := range CleanListings(listings) {
+		if l.ID >= 3 && l.PricePerNight != 0 {
+			t.Errorf("listing %d: price per night %v, want none", l.ID, l.PricePerNight)
+		}
+	}
+
+	report := GenerateReport(listings)
+	if report.TotalListings != 4 || report.PricedListings != 2 {
+		t.Errorf("listings %d, priced %d; want 4, 2", report.TotalListings, report.PricedListings)
+	}
+	if report.AveragePrice != 150 || report.MinPrice != 100 || report.MaxPrice != 200 {
+		t.Errorf("average %v, min %v, max %v; want 150, 100, 200", report.AveragePrice, report.MinPrice, report.MaxPrice)
+	}
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+)
+
+// CalendarPath is where CSVWriter puts the calendars of the listings it
+// writes to path: output/listings.csv -> output/listings_calendar.csv.
+func CalendarPath(path string) string {
+	return companionPath(path, "calendar")
+}
+
+// writeCalendar saves the listings' calendars next to the listings file,
+// one row per listing and day, when any were scraped.
+func (w *CSVWriter) writeCalendar(listings []models.Listing) error {
+	count := 0
+	for _, l := range listings {
+		count += len(l.Calendar)
+	}
+	if count == 0 {
+		return nil
+	}
+
+	path := CalendarPath(w.path)
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create calendar file: %w", err)
+	}
+	defer file.Close()
+
+	if err := WriteCalendarCSV(file, listings); err != nil {
+		return err
+	}
+
+	utils.Success("Saved %d calendar days → %s", count, path)
+	return nil
+}
+
+// WriteCalendarCSV writes a header row plus one row per calendar day to
+// out. room_id links a day to its listing in the listings CSV.
+func WriteCalendarCSV(out io.Writer, listings []models.Listing) error {
+	writer := csv.NewWriter(out)
+	writer.Write([]string{"room_id", "date", "available", "min_nights", "price"})
+
+	for _, l := range listings {
+		for _, d := range l.Calendar {
+			minNights := ""
+			if d.MinNights > 0 {
+				minNights = strconv.Itoa(d.MinNights)
+			}
+			price := ""
+			if d.Price > 0 {
+				price = formatFloat(d.Price)
+			}
+			writer.Write([]string{formatID(l.ID), d.Date, strconv.FormatBool(d.Available), minNights, price})
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("csv write error: %w", err)
+	}
+	return nil
+}
+
+// readCalendar attaches the days in a file written by WriteCalendarCSV
+// to the listings with the same room ID. A missing file is not an error.
+func readCalendar(path string, listings []models.Listing) error {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not open calendar csv: %w", err)
+	}
+	defer file.Close()
+
+	index := make(map[int64]int, len(listings))
+	for i, l := range listings {
+		if l.ID != 0 {
+			index[l.ID] = i
+		}
+	}
+
+	reader := csv.NewReader(file)
+	if _, err := reader.Read(); err != nil && err != io.EOF {
+		return fmt.Errorf("could not read calendar csv header: %w", err)
+	}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("calendar csv line %d: %w", line, err)
+		}
+
+		var roomID int64
+		var d models.CalendarDay
+		d.Date = record[1]
+		err = parseID(record[0], &roomID)
+		if err == nil {
+			err = parseBool(record[2], &d.Available)
+		}
+		if err == nil {
+			err = parseInt(record[3], &d.MinNights)
+		}
+		if err == nil {
+			err = parseFloat(record[4], &d.Price)
+		}
+		if err != nil {
+			return fmt.Errorf("calendar csv line %d: %w", line, err)
+		}
+		if i, ok := index[roomID]; ok {
+			listings[i].Calendar = append(listings[i].Calendar, d)
+		}
+	}
+}
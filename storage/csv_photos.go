@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// PhotosPath is where CSVWriter puts the photos of the listings it
+// writes to path: output/listings.csv -> output/listings_photos.csv.
+func PhotosPath(path string) string {
+	return companionPath(path, "photos")
+}
+
+// writePhotos saves the listings' photos next to the listings file, one
+// row per photo, when any were scraped.
+func (w *CSVWriter) writePhotos(listings []models.Listing) error {
+	count := 0
+	for _, l := range listings {
+		count += len(l.Photos)
+	}
+	if count == 0 {
+		return nil
+	}
+
+	path := PhotosPath(w.path)
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create photos file: %w", err)
+	}
+	defer file.Close()
+
+	if err := WritePhotosCSV(file, listings); err != nil {
+		return err
+	}
+
+	utils.Success("Saved %d photos → %s", count, path)
+	return nil
+}
+
+// WritePhotosCSV writes a header row plus one row per photo to out, in
+// page order. hash and file are empty unless the photo was downloaded.
+func WritePhotosCSV(out io.Writer, listings []models.Listing) error {
+	writer := csv.NewWriter(out)
+	writer.Write([]string{"room_id", "position", "url", "caption", "room", "hash", "file"})
+
+	for _, l := range listings {
+		for i, p := range l.Photos {
+			writer.Write([]string{formatID(l.ID), strconv.Itoa(i + 1), p.URL, p.Caption, p.Room, p.Hash, p.File})
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("csv write error: %w", err)
+	}
+	return nil
+}
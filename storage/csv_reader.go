@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"airbnb-scraper/models"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ReadCSV loads listings from a CSV file produced by CSVWriter, with
+// their calendars when the calendar file is next to it. Columns are
+// matched by header name, so files written by older versions (with fewer
+// columns) still load; unknown columns are ignored. A row without a
+// room_id gets the ID from its /rooms/<id> URL.
+func ReadCSV(path string) ([]models.Listing, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open csv: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read csv header: %w", err)
+	}
+
+	byName := make(map[string]csvColumn, len(csvColumns))
+	for _, c := range csvColumns {
+		byName[c.name] = c
+	}
+	columns := make([]*csvColumn, len(header))
+	for i, name := range header {
+		if c, ok := byName[strings.TrimSpace(name)]; ok {
+			columns[i] = &c
+		}
+	}
+
+	var listings []models.Listing
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("csv line %d: %w", line, err)
+		}
+
+		var l models.Listing
+		for i, value := range record {
+			if i >= len(columns) || columns[i] == nil {
+				continue
+			}
+			if err := columns[i].set(&l, value); err != nil {
+				return nil, fmt.Errorf("csv line %d, column %s: %w", line, columns[i].name, err)
+			}
+		}
+		if l.ID == 0 {
+			l.ID = models.RoomID(l.URL)
+		}
+		listings = append(listings, l)
+	}
+
+	if err := readCalendar(CalendarPath(path), listings); err != nil {
+		return nil, err
+	}
+	return listings, nil
+}
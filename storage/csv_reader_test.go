@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadCSVRoomIDFromURL(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+		want []int64
+	}{
+		{
+			name: "no room_id column",
+			csv: "platform,title,url\n" +
+				"airbnb,Loft,https://www.airbnb.com/rooms/41001101?adults=2\n" +
+				"airbnb,Villa,https://www.airbnb.com/rooms/plus/52345\n",
+			want: []int64{41001101, 52345},
+		},
+		{
+			name: "empty room_id",
+			csv: "room_id,title,url\n" +
+				",Loft,https://www.airbnb.com/rooms/41001101\n" +
+				"77,Villa,https://www.airbnb.com/rooms/52345\n",
+			want: []int64{41001101, 77},
+		},
+		{
+			name: "URL without a room",
+			csv: "room_id,title,url\n" +
+				",Search,https://www.airbnb.com/s/Bali/homes\n" +
+				",Blank,\n",
+			want: []int64{0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "listings.csv")
+			if err := os.WriteFile(path, []byte(tt.csv), 0644); err != nil {
+				t.Fatal(err)
+			}
+			listings, err := ReadCSV(path)
+			if err != nil {
+				t.Fatalf("ReadCSV: %v", err)
+			}
+			if len(listings) != len(tt.want) {
+				t.Fatalf("got %d listings, want %d", len(listings), len(tt.want))
+			}
+			for i, l := range listings {
+				if l.ID != tt.want[i] {
+					t.Errorf("row %d: ID %d, want %d", i+1, l.ID, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"airbnb-scraper/models"
+	"airbnb-scraper/utils"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ReviewsPath is where CSVWriter puts the reviews of the listings it
+// writes to path: output/listings.csv -> output/listings_reviews.csv.
+func ReviewsPath(path string) string {
+	return companionPath(path, "reviews")
+}
+
+// companionPath names a file written next to the listings file.
+func companionPath(path, name string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "_" + name + ext
+}
+
+// writeReviews saves the listings' reviews next to the listings file,
+// one row per review, when any were scraped.
+func (w *CSVWriter) writeReviews(listings []models.Listing) error {
+	count := 0
+	for _, l := range listings {
+		count += len(l.Reviews)
+	}
+	if count == 0 {
+		return nil
+	}
+
+	path := ReviewsPath(w.path)
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create reviews file: %w", err)
+	}
+	defer file.Close()
+
+	if err := WriteReviewsCSV(file, listings); err != nil {
+		return err
+	}
+
+	utils.Success("Saved %d reviews → %s", count, path)
+	return nil
+}
+
+// WriteReviewsCSV writes a header row plus one row per review to out.
+// room_id links a review to its listing in the listings CSV.
+func WriteReviewsCSV(out io.Writer, listings []models.Listing) error {
+	writer := csv.NewWriter(out)
+	writer.Write([]string{"room_id", "review_id", "reviewer_name", "date", "language", "rating", "text", "host_response"})
+
+	for _, l := range listings {
+		for _, r := range l.Reviews {
+			rating := ""
+			if r.Rating > 0 {
+				rating = strconv.Itoa(r.Rating)
+			}
+			writer.Write([]string{formatID(l.ID), r.ID, r.ReviewerName, r.Date, r.Language, rating, r.Text, r.HostResponse})
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("csv write error: %w", err)
+	}
+	return nil
+}
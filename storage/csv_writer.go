@@ -5,9 +5,11 @@ import (
 	"airbnb-scraper/utils"
 	"encoding/csv"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 )
 
 // CSVWriter saves listings to a CSV file.
@@ -20,10 +22,83 @@ func NewCSVWriter(path string) *CSVWriter {
 	return &CSVWriter{path: path}
 }
 
+// csvColumn describes one CSV column: how to render it from a listing and
+// how to read it back. The writer and ReadCSV both use csvColumns so the
+// two can never drift apart.
+type csvColumn struct {
+	name string
+	get  func(l models.Listing) string
+	set  func(l *models.Listing, v string) error
+}
+
+var csvColumns = []csvColumn{
+	{"room_id", func(l models.Listing) string { return formatID(l.ID) }, func(l *models.Listing, v string) error { return parseID(v, &l.ID) }},
+	{"platform", func(l models.Listing) string { return l.Platform }, func(l *models.Listing, v string) error { l.Platform = v; return nil }},
+	{"title", func(l models.Listing) string { return l.Title }, func(l *models.Listing, v string) error { l.Title = v; return nil }},
+	{"price", func(l models.Listing) string { return formatFloat(l.Price) }, func(l *models.Listing, v string) error { return parseFloat(v, &l.Price) }},
+	{"raw_price", func(l models.Listing) string { return l.RawPrice }, func(l *models.Listing, v string) error { l.RawPrice = v; return nil }},
+	{"price_per_night", func(l models.Listing) string { return formatFloat(l.PricePerNight) }, func(l *models.Listing, v string) error { return parseFloat(v, &l.PricePerNight) }},
+	{"check_in", func(l models.Listing) string { return l.CheckIn }, func(l *models.Listing, v string) error { l.CheckIn = v; return nil }},
+	{"check_out", func(l models.Listing) string { return l.CheckOut }, func(l *models.Listing, v string) error { l.CheckOut = v; return nil }},
+	{"nights", func(l models.Listing) string { return strconv.Itoa(l.Nights) }, func(l *models.Listing, v string) error { return parseInt(v, &l.Nights) }},
+	{"nightly_rate", func(l models.Listing) string { return formatFloat(l.NightlyRate) }, func(l *models.Listing, v string) error { return parseFloat(v, &l.NightlyRate) }},
+	{"cleaning_fee", func(l models.Listing) string { return formatFloat(l.CleaningFee) }, func(l *models.Listing, v string) error { return parseFloat(v, &l.CleaningFee) }},
+	{"service_fee", func(l models.Listing) string { return formatFloat(l.ServiceFee) }, func(l *models.Listing, v string) error { return parseFloat(v, &l.ServiceFee) }},
+	{"taxes", func(l models.Listing) string { return formatFloat(l.Taxes) }, func(l *models.Listing, v string) error { return parseFloat(v, &l.Taxes) }},
+	{"discount", func(l models.Listing) string { return formatFloat(l.Discount) }, func(l *models.Listing, v string) error { return parseFloat(v, &l.Discount) }},
+	{"total_price", func(l models.Listing) string { return formatFloat(l.TotalPrice) }, func(l *models.Listing, v string) error { return parseFloat(v, &l.TotalPrice) }},
+	{"location", func(l models.Listing) string { return l.Location }, func(l *models.Listing, v string) error { l.Location = v; return nil }},
+	{"latitude", func(l models.Listing) string { return formatCoordinate(l.Latitude) }, func(l *models.Listing, v string) error { return parseFloat(v, &l.Latitude) }},
+	{"longitude", func(l models.Listing) string { return formatCoordinate(l.Longitude) }, func(l *models.Listing, v string) error { return parseFloat(v, &l.Longitude) }},
+	{"neighborhood", func(l models.Listing) string { return l.Neighborhood }, func(l *models.Listing, v string) error { l.Neighborhood = v; return nil }},
+	{"city", func(l models.Listing) string { return l.City }, func(l *models.Listing, v string) error { l.City = v; return nil }},
+	{"region", func(l models.Listing) string { return l.Region }, func(l *models.Listing, v string) error { l.Region = v; return nil }},
+	{"country", func(l models.Listing) string { return l.Country }, func(l *models.Listing, v string) error { l.Country = v; return nil }},
+	{"rating", func(l models.Listing) string { return formatFloat(l.Rating) }, func(l *models.Listing, v string) error { return parseFloat(v, &l.Rating) }},
+	{"review_count", func(l models.Listing) string { return strconv.Itoa(l.ReviewCount) }, func(l *models.Listing, v string) error { return parseInt(v, &l.ReviewCount) }},
+	{"score_cleanliness", func(l models.Listing) string { return formatFloat(l.Scores.Cleanliness) }, func(l *models.Listing, v string) error { return parseFloat(v, &l.Scores.Cleanliness) }},
+	{"score_accuracy", func(l models.Listing) string { return formatFloat(l.Scores.Accuracy) }, func(l *models.Listing, v string) error { return parseFloat(v, &l.Scores.Accuracy) }},
+	{"score_check_in", func(l models.Listing) string { return formatFloat(l.Scores.CheckIn) }, func(l *models.Listing, v string) error { return parseFloat(v, &l.Scores.CheckIn) }},
+	{"score_communication", func(l models.Listing) string { return formatFloat(l.Scores.Communication) }, func(l *models.Listing, v string) error { return parseFloat(v, &l.Scores.Communication) }},
+	{"score_location", func(l models.Listing) string { return formatFloat(l.Scores.Location) }, func(l *models.Listing, v string) error { return parseFloat(v, &l.Scores.Location) }},
+	{"score_value", func(l models.Listing) string { return formatFloat(l.Scores.Value) }, func(l *models.Listing, v string) error { return parseFloat(v, &l.Scores.Value) }},
+	{"room_type", func(l models.Listing) string { return l.RoomType }, func(l *models.Listing, v string) error { l.RoomType = v; return nil }},
+	{"property_type", func(l models.Listing) string { return l.PropertyType }, func(l *models.Listing, v string) error { l.PropertyType = v; return nil }},
+	{"guests", func(l models.Listing) string { return strconv.Itoa(l.Guests) }, func(l *models.Listing, v string) error { return parseInt(v, &l.Guests) }},
+	{"bedrooms", func(l models.Listing) string { return strconv.Itoa(l.Bedrooms) }, func(l *models.Listing, v string) error { return parseInt(v, &l.Bedrooms) }},
+	{"beds", func(l models.Listing) string { return strconv.Itoa(l.Beds) }, func(l *models.Listing, v string) error { return parseInt(v, &l.Beds) }},
+	{"bathrooms", func(l models.Listing) string { return formatFloat(l.Bathrooms) }, func(l *models.Listing, v string) error { return parseFloat(v, &l.Bathrooms) }},
+	{"shared_bath", func(l models.Listing) string { return strconv.FormatBool(l.SharedBath) }, func(l *models.Listing, v string) error { return parseBool(v, &l.SharedBath) }},
+	{"amenities", func(l models.Listing) string { return formatAmenities(l.Amenities, true) }, func(l *models.Listing, v string) error { return parseAmenities(v, true, &l.Amenities) }},
+	{"amenities_unavailable", func(l models.Listing) string { return formatAmenities(l.Amenities, false) }, func(l *models.Listing, v string) error { return parseAmenities(v, false, &l.Amenities) }},
+	{"host_id", func(l models.Listing) string { return formatID(l.Host.ID) }, func(l *models.Listing, v string) error { return parseID(v, &l.Host.ID) }},
+	{"host_name", func(l models.Listing) string { return l.Host.Name }, func(l *models.Listing, v string) error { l.Host.Name = v; return nil }},
+	{"host_superhost", func(l models.Listing) string { return strconv.FormatBool(l.Host.Superhost) }, func(l *models.Listing, v string) error { return parseBool(v, &l.Host.Superhost) }},
+	{"host_years", func(l models.Listing) string { return strconv.Itoa(l.Host.YearsHosting) }, func(l *models.Listing, v string) error { return parseInt(v, &l.Host.YearsHosting) }},
+	{"host_response_rate", func(l models.Listing) string { return strconv.Itoa(l.Host.ResponseRate) }, func(l *models.Listing, v string) error { return parseInt(v, &l.Host.ResponseRate) }},
+	{"host_response_time", func(l models.Listing) string { return l.Host.ResponseTime }, func(l *models.Listing, v string) error { l.Host.ResponseTime = v; return nil }},
+	{"host_listings", func(l models.Listing) string { return strconv.Itoa(l.Host.ListingCount) }, func(l *models.Listing, v string) error { return parseInt(v, &l.Host.ListingCount) }},
+	{"host_professional", func(l models.Listing) string { return strconv.FormatBool(l.Host.Professional) }, func(l *models.Listing, v string) error { return parseBool(v, &l.Host.Professional) }},
+	{"check_in_from", func(l models.Listing) string { return l.Policies.CheckInFrom }, func(l *models.Listing, v string) error { l.Policies.CheckInFrom = v; return nil }},
+	{"check_in_until", func(l models.Listing) string { return l.Policies.CheckInUntil }, func(l *models.Listing, v string) error { l.Policies.CheckInUntil = v; return nil }},
+	{"checkout_by", func(l models.Listing) string { return l.Policies.CheckoutBy }, func(l *models.Listing, v string) error { l.Policies.CheckoutBy = v; return nil }},
+	{"max_guests", func(l models.Listing) string { return strconv.Itoa(l.Policies.MaxGuests) }, func(l *models.Listing, v string) error { return parseInt(v, &l.Policies.MaxGuests) }},
+	{"pets_allowed", func(l models.Listing) string { return formatFlag(l.Policies.PetsAllowed) }, func(l *models.Listing, v string) error { return parseFlag(v, &l.Policies.PetsAllowed) }},
+	{"smoking_allowed", func(l models.Listing) string { return formatFlag(l.Policies.SmokingAllowed) }, func(l *models.Listing, v string) error { return parseFlag(v, &l.Policies.SmokingAllowed) }},
+	{"parties_allowed", func(l models.Listing) string { return formatFlag(l.Policies.PartiesAllowed) }, func(l *models.Listing, v string) error { return parseFlag(v, &l.Policies.PartiesAllowed) }},
+	{"smoke_alarm", func(l models.Listing) string { return formatFlag(l.Policies.SmokeAlarm) }, func(l *models.Listing, v string) error { return parseFlag(v, &l.Policies.SmokeAlarm) }},
+	{"co_alarm", func(l models.Listing) string { return formatFlag(l.Policies.COAlarm) }, func(l *models.Listing, v string) error { return parseFlag(v, &l.Policies.COAlarm) }},
+	{"security_cameras", func(l models.Listing) string { return formatFlag(l.Policies.SecurityCameras) }, func(l *models.Listing, v string) error { return parseFlag(v, &l.Policies.SecurityCameras) }},
+	{"cancellation_policy", func(l models.Listing) string { return l.Policies.Cancellation }, func(l *models.Listing, v string) error { l.Policies.Cancellation = v; return nil }},
+	{"url", func(l models.Listing) string { return l.URL }, func(l *models.Listing, v string) error { l.URL = v; return nil }},
+	{"thumbnail", func(l models.Listing) string { return l.Thumbnail }, func(l *models.Listing, v string) error { l.Thumbnail = v; return nil }},
+	{"description", func(l models.Listing) string { return l.Description }, func(l *models.Listing, v string) error { l.Description = v; return nil }},
+}
+
 // Write saves all listings to the CSV file.
 // Creates the output directory if it does not exist.
 //
-// CSV columns: platform, title, price, raw_price, location, rating, url
+// CSV columns: see csvColumns (room_id, platform, title, price, raw_price, ...)
 func (w *CSVWriter) Write(listings []models.Listing) error {
 	if len(listings) == 0 {
 		utils.Warn("No listings to write")
@@ -41,32 +116,165 @@ func (w *CSVWriter) Write(listings []models.Listing) error {
 	}
 	defer file.Close()
 
+	if err := WriteCSV(file, listings); err != nil {
+		return err
+	}
+
+	utils.Success("Saved %d listings → %s", len(listings), w.path)
+	if err := w.writeCalendar(listings); err != nil {
+		return err
+	}
+	if err := w.writePhotos(listings); err != nil {
+		return err
+	}
+	return w.writeReviews(listings)
+}
+
+// WriteCSV writes a header row plus one row per listing to out.
+func WriteCSV(out io.Writer, listings []models.Listing) error {
 	// csv.NewWriter handles quoting, commas inside fields, line endings
-	writer := csv.NewWriter(file)
-	defer writer.Flush() // IMPORTANT — must flush or data stays in buffer
+	writer := csv.NewWriter(out)
 
-	// Header row
-	writer.Write([]string{"platform", "title", "price", "raw_price", "location", "rating", "url", "description"})
+	header := make([]string, len(csvColumns))
+	for i, c := range csvColumns {
+		header[i] = c.name
+	}
+	writer.Write(header)
 
 	// One row per listing
 	for _, l := range listings {
-		writer.Write([]string{
-			l.Platform,
-			l.Title,
-			strconv.FormatFloat(l.Price, 'f', 2, 64),
-			l.RawPrice,
-			l.Location,
-			strconv.FormatFloat(l.Rating, 'f', 2, 64),
-			l.URL,
-			l.Description,
-		})
+		row := make([]string, len(csvColumns))
+		for i, c := range csvColumns {
+			row[i] = c.get(l)
+		}
+		writer.Write(row)
 	}
 
+	writer.Flush() // IMPORTANT — must flush or data stays in buffer
+
 	// Check if any writes failed
 	if err := writer.Error(); err != nil {
 		return fmt.Errorf("csv write error: %w", err)
 	}
+	return nil
+}
 
-	utils.Success("Saved %d listings → %s", len(listings), w.path)
+// amenitySeparator joins amenity names in the amenities columns. Amenity
+// names contain commas ("Shampoo, conditioner") but not semicolons.
+const amenitySeparator = "; "
+
+// formatAmenities lists the available (or unavailable) amenity names.
+// Categories are not kept in CSV.
+func formatAmenities(amenities []models.Amenity, available bool) string {
+	var names []string
+	for _, a := range amenities {
+		if a.Available == available {
+			names = append(names, a.Name)
+		}
+	}
+	return strings.Join(names, amenitySeparator)
+}
+
+// parseAmenities appends the names in one amenities column to dst.
+func parseAmenities(s string, available bool, dst *[]models.Amenity) error {
+	for _, name := range strings.Split(s, ";") {
+		if name = strings.TrimSpace(name); name != "" {
+			*dst = append(*dst, models.Amenity{Name: name, Available: available})
+		}
+	}
+	return nil
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// formatCoordinate keeps full precision and leaves unknown coordinates
+// (0) empty.
+func formatCoordinate(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// formatID leaves unknown room IDs (0) empty rather than writing "0".
+func formatID(v int64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+func parseID(s string, dst *int64) error {
+	if s == "" {
+		*dst = 0
+		return nil
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid ID %q", s)
+	}
+	*dst = v
+	return nil
+}
+
+func parseInt(s string, dst *int) error {
+	if s == "" {
+		*dst = 0
+		return nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("invalid integer %q", s)
+	}
+	*dst = v
+	return nil
+}
+
+func parseFloat(s string, dst *float64) error {
+	if s == "" {
+		*dst = 0
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("invalid number %q", s)
+	}
+	*dst = v
+	return nil
+}
+
+func parseBool(s string, dst *bool) error {
+	if s == "" {
+		*dst = false
+		return nil
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return fmt.Errorf("invalid boolean %q", s)
+	}
+	*dst = v
 	return nil
-}
\ No newline at end of file
+}
+
+// formatFlag leaves flags the page did not state (nil) empty.
+func formatFlag(v *bool) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatBool(*v)
+}
+
+func parseFlag(s string, dst **bool) error {
+	if s == "" {
+		*dst = nil
+		return nil
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return fmt.Errorf("invalid boolean %q", s)
+	}
+	*dst = &v
+	return nil
+}
@@ -0,0 +1,22 @@
+package storage
+
+import (
+	"airbnb-scraper/models"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// WriteJSON writes listings to out as an indented JSON array.
+func WriteJSON(out io.Writer, listings []models.Listing) error {
+	if listings == nil {
+		listings = []models.Listing{}
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(listings); err != nil {
+		return fmt.Errorf("json write error: %w", err)
+	}
+	return nil
+}
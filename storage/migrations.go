@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"airbnb-scraper/models"
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// migration is a one-time change to rows stored by an earlier version.
+// Schema changes that are safe to repeat (ADD COLUMN IF NOT EXISTS, ...)
+// stay in EnsureSchema; a migration rewrites data, so it is recorded in
+// schema_migrations and runs once per database.
+type migration struct {
+	name string
+	run  func(ctx context.Context, tx pgx.Tx) error
+}
+
+// migrations run in order, after the schema is up to date.
+var migrations = []migration{
+	{"room_id_identity", migrateRoomIDs},
+}
+
+// migrate applies the migrations not yet recorded. Each one runs in its
+// own transaction together with its schema_migrations row, so it is
+// either applied and recorded or neither; a second process starting at
+// the same time waits on that row and then skips the migration.
+func (w *PostgresWriter) migrate(ctx context.Context) error {
+	_, err := w.pool.Exec(ctx, `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		name TEXT PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	for _, m := range migrations {
+		if err := w.applyMigration(ctx, m); err != nil {
+			return fmt.Errorf("migration %s failed: %w", m.name, err)
+		}
+	}
+	return nil
+}
+
+func (w *PostgresWriter) applyMigration(ctx context.Context, m migration) error {
+	tx, err := w.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `INSERT INTO schema_migrations (name) VALUES ($1) ON CONFLICT (name) DO NOTHING`, m.name)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+	if err := m.run(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// migrateRoomIDs moves listings stored before the room ID was their
+// identity onto it: room_id is backfilled from the stored URLs, rows that
+// are the same room under different tracking URLs are merged into the
+// latest one (keeping all observations), URLs are canonicalized to
+// <origin>/rooms/<id>, and room_id becomes unique.
+func migrateRoomIDs(ctx context.Context, tx pgx.Tx) error {
+	steps := []struct {
+		sql  string
+		args []interface{}
+	}{
+		{`
+		UPDATE listings
+		SET room_id = substring(url FROM $1)::BIGINT
+		WHERE room_id IS NULL AND url ~ $1`, []interface{}{models.RoomPathPattern}},
+
+		{`
+		UPDATE listing_observations o SET listing_id = d.keep_id
+		FROM (
+			SELECT id, FIRST_VALUE(id) OVER (PARTITION BY room_id ORDER BY updated_at DESC, id DESC) AS keep_id
+			FROM listings WHERE room_id IS NOT NULL
+		) d
+		WHERE o.listing_id = d.id AND d.id <> d.keep_id`, nil},
+
+		{`
+		DELETE FROM listings l
+		USING (
+			SELECT id, FIRST_VALUE(id) OVER (PARTITION BY room_id ORDER BY updated_at DESC, id DESC) AS keep_id
+			FROM listings WHERE room_id IS NOT NULL
+		) d
+		WHERE l.id = d.id AND d.id <> d.keep_id`, nil},
+
+		{`
+		UPDATE listings
+		SET url = regexp_replace(url, $1, '\1/rooms/\2')
+		WHERE room_id IS NOT NULL AND url !~ '^https?://[^/]+/rooms/[0-9]+$'`,
+			[]interface{}{`^(https?://[^/]+)` + models.RoomPathPattern + `.*$`}},
+
+		{`CREATE UNIQUE INDEX IF NOT EXISTS idx_listings_room_id ON listings(room_id)`, nil},
+	}
+
+	for _, step := range steps {
+		if _, err := tx.Exec(ctx, step.sql, step.args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"airbnb-scraper/models"
+	"context"
+	"fmt"
+	"time"
+)
+
+// DBStats summarises what is currently stored in PostgreSQL.
+type DBStats struct {
+	TotalListings  int
+	Observations   int
+	ByPlatform     map[string]int
+	Locations      int
+	FirstScrapedAt *time.Time
+	LastScrapedAt  *time.Time
+}
+
+// ReadListings loads every stored listing with its amenities and the
+// calendar days of its latest calendar scrape, oldest first. Reviews and
+// photos are not loaded; query the reviews and listing_photos tables for
+// them.
+func (w *PostgresWriter) ReadListings() ([]models.Listing, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	rows, err := w.pool.Query(ctx, `
+	SELECT l.id, COALESCE(l.room_id, 0), l.platform, l.title, COALESCE(l.price, 0), COALESCE(l.raw_price, ''), COALESCE(l.location, ''),
+	       COALESCE(l.rating, 0), COALESCE(l.review_count, 0), l.url, COALESCE(l.description, ''),
+	       COALESCE(l.room_type, ''), COALESCE(l.property_type, ''), COALESCE(l.guests, 0), COALESCE(l.bedrooms, 0),
+	       COALESCE(l.beds, 0), COALESCE(l.bathrooms, 0), COALESCE(l.shared_bath, FALSE),
+	       COALESCE(h.host_id, 0), COALESCE(h.name, ''), COALESCE(h.superhost, FALSE), COALESCE(h.years_hosting, 0),
+	       COALESCE(h.response_rate, 0), COALESCE(h.response_time, ''), COALESCE(h.listing_count, 0), COALESCE(h.professional, FALSE),
+	       COALESCE(l.latitude, 0), COALESCE(l.longitude, 0), COALESCE(l.neighborhood, ''), COALESCE(l.city, ''),
+	       COALESCE(l.region, ''), COALESCE(l.country, ''),
+	       COALESCE(to_char(l.check_in, 'YYYY-MM-DD'), ''), COALESCE(to_char(l.check_out, 'YYYY-MM-DD'), ''), COALESCE(l.nights, 0),
+	       COALESCE(l.nightly_rate, 0), COALESCE(l.cleaning_fee, 0), COALESCE(l.service_fee, 0), COALESCE(l.taxes, 0),
+	       COALESCE(l.discount, 0), COALESCE(l.total_price, 0), COALESCE(l.price_per_night, 0),
+	       COALESCE(l.score_cleanliness, 0), COALESCE(l.score_accuracy, 0), COALESCE(l.score_check_in, 0),
+	       COALESCE(l.score_communication, 0), COALESCE(l.score_location, 0), COALESCE(l.score_value, 0),
+	       COALESCE(to_char(l.check_in_from, 'HH24:MI'), ''), COALESCE(to_char(l.check_in_until, 'HH24:MI'), ''),
+	       COALESCE(to_char(l.checkout_by, 'HH24:MI'), ''), COALESCE(l.max_guests, 0),
+	       l.pets_allowed, l.smoking_allowed, l.parties_allowed, l.smoke_alarm, l.co_alarm, l.security_cameras,
+	       COALESCE(l.cancellation_policy, ''), COALESCE(l.thumbnail_url, '')
+	FROM listings l
+	LEFT JOIN hosts h ON h.host_id = l.host_id
+	ORDER BY l.id;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query listings: %w", err)
+	}
+	defer rows.Close()
+
+	var listings []models.Listing
+	index := make(map[int64]int) // listings.id -> position in listings
+	for rows.Next() {
+		var l models.Listing
+		var id int64
+		if err := rows.Scan(&id, &l.ID, &l.Platform, &l.Title, &l.Price, &l.RawPrice, &l.Location, &l.Rating, &l.ReviewCount, &l.URL, &l.Description,
+			&l.RoomType, &l.PropertyType, &l.Guests, &l.Bedrooms, &l.Beds, &l.Bathrooms, &l.SharedBath,
+			&l.Host.ID, &l.Host.Name, &l.Host.Superhost, &l.Host.YearsHosting,
+			&l.Host.ResponseRate, &l.Host.ResponseTime, &l.Host.ListingCount, &l.Host.Professional,
+			&l.Latitude, &l.Longitude, &l.Neighborhood, &l.City, &l.Region, &l.Country,
+			&l.CheckIn, &l.CheckOut, &l.Nights,
+			&l.NightlyRate, &l.CleaningFee, &l.ServiceFee, &l.Taxes,
+			&l.Discount, &l.TotalPrice, &l.PricePerNight,
+			&l.Scores.Cleanliness, &l.Scores.Accuracy, &l.Scores.CheckIn,
+			&l.Scores.Communication, &l.Scores.Location, &l.Scores.Value,
+			&l.Policies.CheckInFrom, &l.Policies.CheckInUntil, &l.Policies.CheckoutBy, &l.Policies.MaxGuests,
+			&l.Policies.PetsAllowed, &l.Policies.SmokingAllowed, &l.Policies.PartiesAllowed,
+			&l.Policies.SmokeAlarm, &l.Policies.COAlarm, &l.Policies.SecurityCameras,
+			&l.Policies.Cancellation, &l.Thumbnail); err != nil {
+			return nil, fmt.Errorf("failed to scan listing: %w", err)
+		}
+		index[id] = len(listings)
+		listings = append(listings, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read listings: %w", err)
+	}
+	rows.Close()
+
+	rows, err = w.pool.Query(ctx, `
+	SELECT la.listing_id, a.name, COALESCE(a.category, ''), la.available
+	FROM listing_amenities la
+	JOIN amenities a ON a.id = la.amenity_id
+	ORDER BY la.listing_id, la.available DESC, a.category, a.name;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query amenities: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var a models.Amenity
+		if err := rows.Scan(&id, &a.Name, &a.Category, &a.Available); err != nil {
+			return nil, fmt.Errorf("failed to scan amenity: %w", err)
+		}
+		if i, ok := index[id]; ok {
+			listings[i].Amenities = append(listings[i].Amenities, a)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read amenities: %w", err)
+	}
+	rows.Close()
+
+	rows, err = w.pool.Query(ctx, `
+	WITH latest AS (
+		SELECT DISTINCT ON (listing_id) listing_id, run_id
+		FROM listing_calendar
+		ORDER BY listing_id, scraped_at DESC
+	)
+	SELECT c.listing_id, to_char(c.day, 'YYYY-MM-DD'), c.available, COALESCE(c.min_nights, 0), COALESCE(c.price, 0)
+	FROM listing_calendar c
+	JOIN latest ON latest.listing_id = c.listing_id AND c.run_id IS NOT DISTINCT FROM latest.run_id
+	ORDER BY c.listing_id, c.day;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query calendar: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var d models.CalendarDay
+		if err := rows.Scan(&id, &d.Date, &d.Available, &d.MinNights, &d.Price); err != nil {
+			return nil, fmt.Errorf("failed to scan calendar day: %w", err)
+		}
+		if i, ok := index[id]; ok {
+			listings[i].Calendar = append(listings[i].Calendar, d)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+
+	return listings, nil
+}
+
+// Stats returns row counts and the time range covered by stored listings.
+func (w *PostgresWriter) Stats() (DBStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	stats := DBStats{ByPlatform: make(map[string]int)}
+
+	err := w.pool.QueryRow(ctx, `
+	SELECT COUNT(*), COUNT(DISTINCT location), MIN(created_at), MAX(updated_at)
+	FROM listings;
+	`).Scan(&stats.TotalListings, &stats.Locations, &stats.FirstScrapedAt, &stats.LastScrapedAt)
+	if err != nil {
+		return DBStats{}, fmt.Errorf("failed to query listing stats: %w", err)
+	}
+
+	err = w.pool.QueryRow(ctx, `SELECT COUNT(*) FROM listing_observations;`).Scan(&stats.Observations)
+	if err != nil {
+		return DBStats{}, fmt.Errorf("failed to count observations: %w", err)
+	}
+
+	rows, err := w.pool.Query(ctx, `SELECT platform, COUNT(*) FROM listings GROUP BY platform;`)
+	if err != nil {
+		return DBStats{}, fmt.Errorf("failed to query platform counts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var platform string
+		var count int
+		if err := rows.Scan(&platform, &count); err != nil {
+			return DBStats{}, fmt.Errorf("failed to scan platform count: %w", err)
+		}
+		stats.ByPlatform[platform] = count
+	}
+	if err := rows.Err(); err != nil {
+		return DBStats{}, fmt.Errorf("failed to read platform counts: %w", err)
+	}
+
+	return stats, nil
+}
@@ -4,6 +4,8 @@ import (
 	"airbnb-scraper/config"
 	"airbnb-scraper/models"
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"time"
@@ -54,6 +56,20 @@ func (w *PostgresWriter) EnsureSchema() error {
 	defer cancel()
 
 	sql := `
+	CREATE TABLE IF NOT EXISTS scrape_runs (
+		id TEXT PRIMARY KEY,
+		started_at TIMESTAMPTZ NOT NULL,
+		finished_at TIMESTAMPTZ,
+		status TEXT NOT NULL,
+		version TEXT NOT NULL,
+		config JSONB NOT NULL,
+		sections_visited INTEGER NOT NULL DEFAULT 0,
+		properties_scraped INTEGER NOT NULL DEFAULT 0,
+		properties_failed INTEGER NOT NULL DEFAULT 0,
+		listings_saved INTEGER NOT NULL DEFAULT 0,
+		error_summary TEXT
+	);
+
 	CREATE TABLE IF NOT EXISTS listings (
 		id BIGSERIAL PRIMARY KEY,
 		platform TEXT NOT NULL,
@@ -69,16 +85,221 @@ func (w *PostgresWriter) EnsureSchema() error {
 
 	CREATE INDEX IF NOT EXISTS idx_listings_price ON listings(price);
 	CREATE INDEX IF NOT EXISTS idx_listings_location ON listings(location);
+
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS run_id TEXT REFERENCES scrape_runs(id);
+	CREATE INDEX IF NOT EXISTS idx_listings_run_id ON listings(run_id);
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+	-- One row per listing per run: listings holds the latest values, this
+	-- table keeps how price and rating moved over time.
+	CREATE TABLE IF NOT EXISTS listing_observations (
+		id BIGSERIAL PRIMARY KEY,
+		listing_id BIGINT NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+		run_id TEXT REFERENCES scrape_runs(id),
+		observed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		price NUMERIC(12,2),
+		raw_price TEXT,
+		rating NUMERIC(3,2),
+		review_count INTEGER,
+		check_in DATE,
+		check_out DATE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_listing_observations_listing ON listing_observations(listing_id, observed_at);
+	CREATE INDEX IF NOT EXISTS idx_listing_observations_run ON listing_observations(run_id);
+
+	-- Rows stored before observations existed become their first observation.
+	INSERT INTO listing_observations (listing_id, run_id, observed_at, price, raw_price, rating)
+	SELECT l.id, l.run_id, l.created_at, l.price, l.raw_price, l.rating
+	FROM listings l
+	WHERE NOT EXISTS (SELECT 1 FROM listing_observations o WHERE o.listing_id = l.id);
+
+	-- Room ID identity; rows stored before it existed are migrated once
+	-- (see migrateRoomIDs), which also creates the unique index.
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS room_id BIGINT;
+
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS review_count INTEGER;
+
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS room_type TEXT;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS property_type TEXT;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS guests INTEGER;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS bedrooms INTEGER;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS beds INTEGER;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS bathrooms NUMERIC(4,1);
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS shared_bath BOOLEAN;
+
+	-- Amenities are shared between listings; listing_amenities links them
+	-- and records whether the listing marks the amenity unavailable.
+	CREATE TABLE IF NOT EXISTS amenities (
+		id BIGSERIAL PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE,
+		category TEXT
+	);
+
+	CREATE TABLE IF NOT EXISTS listing_amenities (
+		listing_id BIGINT NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+		amenity_id BIGINT NOT NULL REFERENCES amenities(id),
+		available BOOLEAN NOT NULL DEFAULT TRUE,
+		PRIMARY KEY (listing_id, amenity_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_listing_amenities_amenity ON listing_amenities(amenity_id);
+
+	CREATE TABLE IF NOT EXISTS hosts (
+		host_id BIGINT PRIMARY KEY,
+		name TEXT,
+		superhost BOOLEAN NOT NULL DEFAULT FALSE,
+		years_hosting INTEGER,
+		response_rate INTEGER,
+		response_time TEXT,
+		listing_count INTEGER,
+		professional BOOLEAN NOT NULL DEFAULT FALSE,
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS host_id BIGINT REFERENCES hosts(host_id);
+	CREATE INDEX IF NOT EXISTS idx_listings_host_id ON listings(host_id);
+
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS neighborhood TEXT;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS city TEXT;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS region TEXT;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS country TEXT;
+	CREATE INDEX IF NOT EXISTS idx_listings_city ON listings(country, city);
+
+	-- Price breakdown. price is the headline figure as the page showed it
+	-- (a nightly rate or a stay total); price_per_night is comparable
+	-- across listings, and NULL when the page did not say what price was
+	-- for. Rows stored before the breakdown was scraped stay NULL too: their
+	-- headline price may be a nightly rate or a stay total.
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS check_in DATE;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS check_out DATE;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS nights INTEGER;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS nightly_rate NUMERIC(12,2);
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS cleaning_fee NUMERIC(12,2);
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS service_fee NUMERIC(12,2);
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS taxes NUMERIC(12,2);
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS discount NUMERIC(12,2);
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS total_price NUMERIC(12,2);
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS price_per_night NUMERIC(12,2);
+	CREATE INDEX IF NOT EXISTS idx_listings_price_per_night ON listings(price_per_night);
+
+	ALTER TABLE listing_observations ADD COLUMN IF NOT EXISTS nights INTEGER;
+	ALTER TABLE listing_observations ADD COLUMN IF NOT EXISTS total_price NUMERIC(12,2);
+	ALTER TABLE listing_observations ADD COLUMN IF NOT EXISTS price_per_night NUMERIC(12,2);
+
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS score_cleanliness NUMERIC(3,2);
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS score_accuracy NUMERIC(3,2);
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS score_check_in NUMERIC(3,2);
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS score_communication NUMERIC(3,2);
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS score_location NUMERIC(3,2);
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS score_value NUMERIC(3,2);
+
+	-- House rules, safety items and cancellation policy. NULL flags mean
+	-- the page did not say.
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS check_in_from TIME;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS check_in_until TIME;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS checkout_by TIME;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS max_guests INTEGER;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS pets_allowed BOOLEAN;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS smoking_allowed BOOLEAN;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS parties_allowed BOOLEAN;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS smoke_alarm BOOLEAN;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS co_alarm BOOLEAN;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS security_cameras BOOLEAN;
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS cancellation_policy TEXT
+		CHECK (cancellation_policy IN ('flexible', 'moderate', 'strict', 'non_refundable'));
+
+	-- The picture of the listing's search results card.
+	ALTER TABLE listings ADD COLUMN IF NOT EXISTS thumbnail_url TEXT;
+
+	-- Reviews accumulate across runs. review_key is Airbnb's review ID, or
+	-- a hash of reviewer and text when the page did not expose one.
+	CREATE TABLE IF NOT EXISTS reviews (
+		id BIGSERIAL PRIMARY KEY,
+		listing_id BIGINT NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+		review_key TEXT NOT NULL,
+		reviewer_name TEXT,
+		review_date DATE,
+		language TEXT,
+		rating INTEGER,
+		text TEXT NOT NULL,
+		host_response TEXT,
+		run_id TEXT REFERENCES scrape_runs(id),
+		scraped_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		UNIQUE (listing_id, review_key)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_reviews_date ON reviews(listing_id, review_date);
+
+	-- One row per listing and day, holding what the latest scrape of that
+	-- day saw. Days already past keep their last state, so a day that
+	-- went from available to blocked shows up as a booking.
+	CREATE TABLE IF NOT EXISTS listing_calendar (
+		listing_id BIGINT NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+		day DATE NOT NULL,
+		available BOOLEAN NOT NULL,
+		min_nights INTEGER,
+		price NUMERIC(12,2),
+		run_id TEXT REFERENCES scrape_runs(id),
+		scraped_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (listing_id, day)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_listing_calendar_day ON listing_calendar(day);
+
+	-- One row per listing and photo URL. first_run_id and run_id are the
+	-- first and latest runs that saw the photo, so photos added or
+	-- dropped since an earlier run show up as a refresh. hash and file
+	-- point into the photo store when photos were downloaded.
+	CREATE TABLE IF NOT EXISTS listing_photos (
+		listing_id BIGINT NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+		url TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		caption TEXT,
+		room TEXT,
+		hash TEXT,
+		file TEXT,
+		first_run_id TEXT REFERENCES scrape_runs(id),
+		run_id TEXT REFERENCES scrape_runs(id),
+		first_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (listing_id, url)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_listing_photos_hash ON listing_photos(hash);
 	`
 
 	if _, err := w.pool.Exec(ctx, sql); err != nil {
 		return fmt.Errorf("failed to ensure schema: %w", err)
 	}
 
-	return nil
+	return w.migrate(ctx)
+}
+
+// BatchMeta describes where a batch of listings came from: the run that
+// scraped them (recorded with StartRun) and, in search mode, the stay
+// dates their prices are for.
+type BatchMeta struct {
+	RunID    string
+	CheckIn  string // YYYY-MM-DD, empty when not searching by date
+	CheckOut string
+
+	// CardsOnly marks listings built from search results cards alone;
+	// they update only the columns a card fills.
+	CardsOnly bool
 }
 
-func (w *PostgresWriter) WriteBatch(listings []models.Listing) error {
+// WriteBatch upserts listings so each row holds the latest values seen,
+// and appends one listing_observations row per listing so earlier
+// prices and ratings are kept. A listing's amenities replace the ones
+// stored for it; listings scraped without amenities keep theirs. Reviews
+// are added to the ones already stored, calendar days overwrite the
+// stored state of the same days, and photos are marked as seen by the
+// run. A cards-only batch leaves the detail page columns (description,
+// capacity, place, host, scores, policies) as they are.
+func (w *PostgresWriter) WriteBatch(meta BatchMeta, listings []models.Listing) error {
 	if len(listings) == 0 {
 		return nil
 	}
@@ -87,13 +308,212 @@ func (w *PostgresWriter) WriteBatch(listings []models.Listing) error {
 	defer cancel()
 
 	batch := &pgx.Batch{}
-	insertSQL := `
-	INSERT INTO listings (platform, title, price, raw_price, location, rating, url, description)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	ON CONFLICT (url) DO NOTHING;
+
+	// Rows are matched on room_id; listings without one (non-room URLs)
+	// fall back to the URL. Capacity values that were not scraped are
+	// stored as NULL; bedrooms only count when guests were found, since a
+	// studio legitimately has 0. Amounts of the price breakdown the page
+	// did not show are NULL too, and so are policy flags it did not state.
+	// Observations take the stay dates the page quoted, falling back to
+	// the run's search dates.
+	//
+	// detailSet updates the columns only a detail page fills; cards-only
+	// batches keep what an earlier full scrape stored. The stay columns a
+	// card may fill keep their stored value when a card had none.
+	detailSet := `
+			nightly_rate = EXCLUDED.nightly_rate,
+			cleaning_fee = EXCLUDED.cleaning_fee,
+			service_fee = EXCLUDED.service_fee,
+			taxes = EXCLUDED.taxes,
+			discount = EXCLUDED.discount,
+			location = EXCLUDED.location,
+			description = EXCLUDED.description,
+			room_type = EXCLUDED.room_type,
+			property_type = EXCLUDED.property_type,
+			guests = EXCLUDED.guests,
+			bedrooms = EXCLUDED.bedrooms,
+			beds = EXCLUDED.beds,
+			bathrooms = EXCLUDED.bathrooms,
+			shared_bath = EXCLUDED.shared_bath,
+			host_id = COALESCE(EXCLUDED.host_id, listings.host_id),
+			latitude = EXCLUDED.latitude,
+			longitude = EXCLUDED.longitude,
+			neighborhood = EXCLUDED.neighborhood,
+			city = EXCLUDED.city,
+			region = EXCLUDED.region,
+			country = EXCLUDED.country,
+			score_cleanliness = EXCLUDED.score_cleanliness,
+			score_accuracy = EXCLUDED.score_accuracy,
+			score_check_in = EXCLUDED.score_check_in,
+			score_communication = EXCLUDED.score_communication,
+			score_location = EXCLUDED.score_location,
+			score_value = EXCLUDED.score_value,
+			check_in_from = EXCLUDED.check_in_from,
+			check_in_until = EXCLUDED.check_in_until,
+			checkout_by = EXCLUDED.checkout_by,
+			max_guests = EXCLUDED.max_guests,
+			pets_allowed = EXCLUDED.pets_allowed,
+			smoking_allowed = EXCLUDED.smoking_allowed,
+			parties_allowed = EXCLUDED.parties_allowed,
+			smoke_alarm = EXCLUDED.smoke_alarm,
+			co_alarm = EXCLUDED.co_alarm,
+			security_cameras = EXCLUDED.security_cameras,
+			cancellation_policy = EXCLUDED.cancellation_policy,`
+	if meta.CardsOnly {
+		detailSet = ""
+	}
+	stayColumn := func(column string) string {
+		if meta.CardsOnly {
+			return column + " = COALESCE(EXCLUDED." + column + ", listings." + column + "),"
+		}
+		return column + " = EXCLUDED." + column + ","
+	}
+	upsertSQL := func(conflict string) string {
+		return `
+	WITH upserted AS (
+		INSERT INTO listings (platform, title, price, raw_price, location, rating, url, description, run_id, room_id, review_count,
+			room_type, property_type, guests, bedrooms, beds, bathrooms, shared_bath, host_id,
+			latitude, longitude, neighborhood, city, region, country,
+			check_in, check_out, nights, nightly_rate, cleaning_fee, service_fee, taxes, discount, total_price, price_per_night,
+			score_cleanliness, score_accuracy, score_check_in, score_communication, score_location, score_value,
+			check_in_from, check_in_until, checkout_by, max_guests, pets_allowed, smoking_allowed, parties_allowed,
+			smoke_alarm, co_alarm, security_cameras, cancellation_policy, thumbnail_url)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $13, $10,
+			NULLIF($14, ''), NULLIF($15, ''), NULLIF($16::int, 0), CASE WHEN $16::int > 0 THEN $17::int END,
+			NULLIF($18::int, 0), NULLIF($19::numeric, 0), CASE WHEN $19::numeric > 0 THEN $20::boolean END,
+			NULLIF($21::bigint, 0),
+			NULLIF($22::float8, 0), NULLIF($23::float8, 0), NULLIF($24, ''), NULLIF($25, ''), NULLIF($26, ''), NULLIF($27, ''),
+			NULLIF($28, '')::date, NULLIF($29, '')::date, NULLIF($30::int, 0), NULLIF($31::numeric, 0), NULLIF($32::numeric, 0),
+			NULLIF($33::numeric, 0), NULLIF($34::numeric, 0), NULLIF($35::numeric, 0), NULLIF($36::numeric, 0), NULLIF($37::numeric, 0),
+			NULLIF($38::numeric, 0), NULLIF($39::numeric, 0), NULLIF($40::numeric, 0), NULLIF($41::numeric, 0),
+			NULLIF($42::numeric, 0), NULLIF($43::numeric, 0),
+			NULLIF($44, '')::time, NULLIF($45, '')::time, NULLIF($46, '')::time, NULLIF($47::int, 0),
+			$48::boolean, $49::boolean, $50::boolean, $51::boolean, $52::boolean, $53::boolean, NULLIF($54, ''),
+			NULLIF($55, ''))
+		ON CONFLICT (` + conflict + `) DO UPDATE SET
+			platform = EXCLUDED.platform,
+			title = EXCLUDED.title,
+			price = EXCLUDED.price,
+			raw_price = EXCLUDED.raw_price,
+			rating = EXCLUDED.rating,
+			review_count = EXCLUDED.review_count,
+			url = EXCLUDED.url,
+			` + stayColumn("check_in") + `
+			` + stayColumn("check_out") + `
+			` + stayColumn("nights") + `
+			` + stayColumn("total_price") + `
+			price_per_night = EXCLUDED.price_per_night,
+			thumbnail_url = COALESCE(EXCLUDED.thumbnail_url, listings.thumbnail_url),` + detailSet + `
+			run_id = EXCLUDED.run_id,
+			updated_at = NOW()
+		RETURNING id
+	)
+	INSERT INTO listing_observations (listing_id, run_id, price, raw_price, rating, review_count, check_in, check_out,
+		nights, total_price, price_per_night)
+	SELECT id, $9, $3, $4, $6, $10,
+		COALESCE(NULLIF($28, ''), NULLIF($11, ''))::date, COALESCE(NULLIF($29, ''), NULLIF($12, ''))::date,
+		NULLIF($30::int, 0), NULLIF($36::numeric, 0), NULLIF($37::numeric, 0)
+	FROM upserted;
 	`
+	}
+	byRoomSQL, byURLSQL := upsertSQL("room_id"), upsertSQL("url")
+
+	// Hosts are upserted before their listings so host_id can reference
+	// them. Values a page did not show keep what is stored.
+	hostSQL := `
+		INSERT INTO hosts (host_id, name, superhost, years_hosting, response_rate, response_time, listing_count, professional)
+		VALUES ($1, NULLIF($2, ''), $3, NULLIF($4::int, 0), NULLIF($5::int, 0), NULLIF($6, ''), NULLIF($7::int, 0), $8)
+		ON CONFLICT (host_id) DO UPDATE SET
+			name = COALESCE(EXCLUDED.name, hosts.name),
+			superhost = EXCLUDED.superhost,
+			years_hosting = COALESCE(EXCLUDED.years_hosting, hosts.years_hosting),
+			response_rate = COALESCE(EXCLUDED.response_rate, hosts.response_rate),
+			response_time = COALESCE(EXCLUDED.response_time, hosts.response_time),
+			listing_count = COALESCE(EXCLUDED.listing_count, hosts.listing_count),
+			professional = EXCLUDED.professional OR hosts.professional,
+			updated_at = NOW();
+		`
+
+	// $1 is the room ID or URL, matching the upsert above.
+	deleteAmenitiesSQL := func(key string) string {
+		return `DELETE FROM listing_amenities WHERE listing_id = (SELECT id FROM listings WHERE ` + key + ` = $1);`
+	}
+	insertAmenitiesSQL := func(key string) string {
+		return `
+		WITH amenity AS (
+			INSERT INTO amenities (name, category)
+			SELECT name, NULLIF(category, '') FROM unnest($2::text[], $3::text[]) AS a(name, category)
+			ON CONFLICT (name) DO UPDATE SET category = COALESCE(EXCLUDED.category, amenities.category)
+			RETURNING id, name
+		)
+		INSERT INTO listing_amenities (listing_id, amenity_id, available)
+		SELECT l.id, amenity.id, a.available
+		FROM listings l
+		CROSS JOIN unnest($2::text[], $4::boolean[]) AS a(name, available)
+		JOIN amenity ON amenity.name = a.name
+		WHERE l.` + key + ` = $1;
+		`
+	}
+
+	// Reviews seen again keep their first date: relative dates ("2 weeks
+	// ago") were most precise when the review was new.
+	insertReviewsSQL := func(key string) string {
+		return `
+		INSERT INTO reviews (listing_id, review_key, reviewer_name, review_date, language, rating, text, host_response, run_id)
+		SELECT l.id, r.key, NULLIF(r.name, ''), NULLIF(r.date, '')::date, NULLIF(r.language, ''), NULLIF(r.rating, 0),
+			r.text, NULLIF(r.response, ''), $9
+		FROM listings l
+		CROSS JOIN unnest($2::text[], $3::text[], $4::text[], $5::text[], $6::int[], $7::text[], $8::text[])
+			AS r(key, name, date, language, rating, text, response)
+		WHERE l.` + key + ` = $1
+		ON CONFLICT (listing_id, review_key) DO UPDATE SET
+			reviewer_name = EXCLUDED.reviewer_name,
+			review_date = COALESCE(reviews.review_date, EXCLUDED.review_date),
+			language = COALESCE(EXCLUDED.language, reviews.language),
+			rating = COALESCE(EXCLUDED.rating, reviews.rating),
+			text = EXCLUDED.text,
+			host_response = COALESCE(EXCLUDED.host_response, reviews.host_response),
+			run_id = EXCLUDED.run_id,
+			scraped_at = NOW();
+		`
+	}
+
+	insertCalendarSQL := func(key string) string {
+		return `
+		INSERT INTO listing_calendar (listing_id, day, available, min_nights, price, run_id)
+		SELECT l.id, d.day::date, d.available, NULLIF(d.min_nights, 0), NULLIF(d.price, 0), $6
+		FROM listings l
+		CROSS JOIN unnest($2::text[], $3::boolean[], $4::int[], $5::numeric[]) AS d(day, available, min_nights, price)
+		WHERE l.` + key + ` = $1
+		ON CONFLICT (listing_id, day) DO UPDATE SET
+			available = EXCLUDED.available,
+			min_nights = EXCLUDED.min_nights,
+			price = EXCLUDED.price,
+			run_id = EXCLUDED.run_id,
+			scraped_at = NOW();
+		`
+	}
+
+	// Photos seen again keep when they were first seen; a hash only
+	// replaces the stored one when the run downloaded the photo.
+	insertPhotosSQL := func(key string) string {
+		return `
+		INSERT INTO listing_photos (listing_id, url, position, caption, room, hash, file, first_run_id, run_id)
+		SELECT l.id, p.url, p.position, NULLIF(p.caption, ''), NULLIF(p.room, ''), NULLIF(p.hash, ''), NULLIF(p.file, ''), $8, $8
+		FROM listings l
+		CROSS JOIN unnest($2::text[], $3::text[], $4::text[], $5::text[], $6::text[]) WITH ORDINALITY AS p(url, caption, room, hash, file, position)
+		WHERE l.` + key + ` = $1
+		ON CONFLICT (listing_id, url) DO UPDATE SET
+			position = EXCLUDED.position,
+			caption = COALESCE(EXCLUDED.caption, listing_photos.caption),
+			room = COALESCE(EXCLUDED.room, listing_photos.room),
+			hash = COALESCE(EXCLUDED.hash, listing_photos.hash),
+			file = COALESCE(EXCLUDED.file, listing_photos.file),
+			run_id = EXCLUDED.run_id,
+			last_seen_at = NOW();
+		`
+	}
 
-	enqueued := 0
 	for _, l := range listings {
 		title := strings.TrimSpace(l.Title)
 		url := strings.TrimSpace(l.URL)
@@ -101,6 +521,16 @@ func (w *PostgresWriter) WriteBatch(listings []models.Listing) error {
 			continue
 		}
 
+		insertSQL, roomID, key, keyValue := byURLSQL, interface{}(nil), "url", interface{}(url)
+		if l.ID != 0 {
+			insertSQL, roomID, key, keyValue = byRoomSQL, l.ID, "room_id", l.ID
+		}
+
+		if h := l.Host; h.ID != 0 {
+			batch.Queue(hostSQL, h.ID, strings.TrimSpace(h.Name), h.Superhost, h.YearsHosting, h.ResponseRate,
+				strings.TrimSpace(h.ResponseTime), h.ListingCount, h.Professional)
+		}
+
 		batch.Queue(
 			insertSQL,
 			strings.TrimSpace(strings.ToLower(l.Platform)),
@@ -111,22 +541,191 @@ func (w *PostgresWriter) WriteBatch(listings []models.Listing) error {
 			l.Rating,
 			url,
 			strings.TrimSpace(l.Description),
+			meta.RunID,
+			l.ReviewCount,
+			meta.CheckIn,
+			meta.CheckOut,
+			roomID,
+			strings.TrimSpace(l.RoomType),
+			strings.TrimSpace(l.PropertyType),
+			l.Guests,
+			l.Bedrooms,
+			l.Beds,
+			l.Bathrooms,
+			l.SharedBath,
+			l.Host.ID,
+			l.Latitude,
+			l.Longitude,
+			strings.TrimSpace(l.Neighborhood),
+			strings.TrimSpace(l.City),
+			strings.TrimSpace(l.Region),
+			strings.TrimSpace(l.Country),
+			l.CheckIn,
+			l.CheckOut,
+			l.Nights,
+			l.NightlyRate,
+			l.CleaningFee,
+			l.ServiceFee,
+			l.Taxes,
+			l.Discount,
+			l.TotalPrice,
+			l.PricePerNight,
+			l.Scores.Cleanliness,
+			l.Scores.Accuracy,
+			l.Scores.CheckIn,
+			l.Scores.Communication,
+			l.Scores.Location,
+			l.Scores.Value,
+			l.Policies.CheckInFrom,
+			l.Policies.CheckInUntil,
+			l.Policies.CheckoutBy,
+			l.Policies.MaxGuests,
+			l.Policies.PetsAllowed,
+			l.Policies.SmokingAllowed,
+			l.Policies.PartiesAllowed,
+			l.Policies.SmokeAlarm,
+			l.Policies.COAlarm,
+			l.Policies.SecurityCameras,
+			l.Policies.Cancellation,
+			l.Thumbnail,
 		)
-		enqueued++
+
+		if names, categories, available := amenityColumns(l.Amenities); len(names) > 0 {
+			batch.Queue(deleteAmenitiesSQL(key), keyValue)
+			batch.Queue(insertAmenitiesSQL(key), keyValue, names, categories, available)
+		}
+
+		if r := reviewColumns(l.Reviews); len(r.keys) > 0 {
+			batch.Queue(insertReviewsSQL(key), keyValue, r.keys, r.names, r.dates, r.languages, r.ratings, r.texts, r.responses, meta.RunID)
+		}
+
+		if c := calendarColumns(l.Calendar); len(c.days) > 0 {
+			batch.Queue(insertCalendarSQL(key), keyValue, c.days, c.available, c.minNights, c.prices, meta.RunID)
+		}
+
+		if p := photoColumns(l.Photos); len(p.urls) > 0 {
+			batch.Queue(insertPhotosSQL(key), keyValue, p.urls, p.captions, p.rooms, p.hashes, p.files, meta.RunID)
+		}
 	}
 
-	if enqueued == 0 {
+	if batch.Len() == 0 {
 		return nil
 	}
 
 	results := w.pool.SendBatch(ctx, batch)
 	defer results.Close()
 
-	for i := 0; i < enqueued; i++ {
+	for i := 0; i < batch.Len(); i++ {
 		if _, err := results.Exec(); err != nil {
-			return fmt.Errorf("batch insert failed at row %d: %w", i, err)
+			return fmt.Errorf("batch upsert failed at statement %d: %w", i, err)
 		}
 	}
 
 	return nil
 }
+
+// amenityColumns splits amenities into parallel arrays for unnest,
+// dropping repeated names (the amenities upsert may touch a row once).
+func amenityColumns(amenities []models.Amenity) ([]string, []string, []bool) {
+	var names, categories []string
+	var available []bool
+	seen := make(map[string]bool)
+	for _, a := range amenities {
+		name := strings.TrimSpace(a.Name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+		categories = append(categories, strings.TrimSpace(a.Category))
+		available = append(available, a.Available)
+	}
+	return names, categories, available
+}
+
+// reviewArrays holds reviews as parallel arrays for unnest.
+type reviewArrays struct {
+	keys, names, dates, languages, texts, responses []string
+	ratings                                         []int
+}
+
+// reviewColumns splits reviews into arrays for unnest, skipping reviews
+// without text and repeated keys (the upsert may touch a row once).
+func reviewColumns(reviews []models.Review) reviewArrays {
+	var a reviewArrays
+	seen := make(map[string]bool)
+	for _, r := range reviews {
+		text := strings.TrimSpace(r.Text)
+		if text == "" {
+			continue
+		}
+		key := strings.TrimSpace(r.ID)
+		if key == "" {
+			sum := sha1.Sum([]byte(strings.TrimSpace(r.ReviewerName) + "\x00" + text))
+			key = "sha1:" + hex.EncodeToString(sum[:])
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		a.keys = append(a.keys, key)
+		a.names = append(a.names, strings.TrimSpace(r.ReviewerName))
+		a.dates = append(a.dates, r.Date)
+		a.languages = append(a.languages, r.Language)
+		a.ratings = append(a.ratings, r.Rating)
+		a.texts = append(a.texts, text)
+		a.responses = append(a.responses, strings.TrimSpace(r.HostResponse))
+	}
+	return a
+}
+
+// calendarArrays holds calendar days as parallel arrays for unnest.
+type calendarArrays struct {
+	days      []string
+	available []bool
+	minNights []int
+	prices    []float64
+}
+
+// calendarColumns splits calendar days into arrays for unnest, skipping
+// repeated dates (the upsert may touch a row once).
+func calendarColumns(calendar []models.CalendarDay) calendarArrays {
+	var a calendarArrays
+	seen := make(map[string]bool)
+	for _, d := range calendar {
+		if d.Date == "" || seen[d.Date] {
+			continue
+		}
+		seen[d.Date] = true
+		a.days = append(a.days, d.Date)
+		a.available = append(a.available, d.Available)
+		a.minNights = append(a.minNights, d.MinNights)
+		a.prices = append(a.prices, d.Price)
+	}
+	return a
+}
+
+// photoArrays holds photos as parallel arrays for unnest, in page order.
+type photoArrays struct {
+	urls, captions, rooms, hashes, files []string
+}
+
+// photoColumns splits photos into arrays for unnest, skipping repeated
+// URLs (the upsert may touch a row once).
+func photoColumns(photos []models.Photo) photoArrays {
+	var a photoArrays
+	seen := make(map[string]bool)
+	for _, p := range photos {
+		u := strings.TrimSpace(p.URL)
+		if u == "" || seen[u] {
+			continue
+		}
+		seen[u] = true
+		a.urls = append(a.urls, u)
+		a.captions = append(a.captions, strings.TrimSpace(p.Caption))
+		a.rooms = append(a.rooms, strings.TrimSpace(p.Room))
+		a.hashes = append(a.hashes, p.Hash)
+		a.files = append(a.files, p.File)
+	}
+	return a
+}
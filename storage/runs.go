@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Scrape run statuses stored in scrape_runs.status.
+const (
+	RunRunning     = "running"
+	RunCompleted   = "completed"
+	RunInterrupted = "interrupted"
+	RunFailed      = "failed"
+)
+
+// ScrapeRun is one row of scrape_runs: who ran what, with which config,
+// and how it went. ID is the run ID also used for checkpoints.
+type ScrapeRun struct {
+	ID         string
+	StartedAt  time.Time
+	FinishedAt *time.Time
+	Status     string
+	Version    string
+	Config     map[string]string // config snapshot, secrets redacted
+
+	SectionsVisited   int
+	PropertiesScraped int
+	PropertiesFailed  int
+	ListingsSaved     int
+	ErrorSummary      string
+}
+
+// StartRun records run as running. Starting an ID that already exists
+// (a resumed run) reopens that row and keeps its original start time.
+func (w *PostgresWriter) StartRun(run ScrapeRun) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := w.pool.Exec(ctx, `
+	INSERT INTO scrape_runs (id, started_at, status, version, config)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (id) DO UPDATE SET
+		status = EXCLUDED.status,
+		finished_at = NULL,
+		version = EXCLUDED.version,
+		config = EXCLUDED.config;
+	`, run.ID, run.StartedAt, RunRunning, run.Version, run.Config)
+	if err != nil {
+		return fmt.Errorf("failed to record run start: %w", err)
+	}
+	return nil
+}
+
+// FinishRun stores the final status and counts of run.
+func (w *PostgresWriter) FinishRun(run ScrapeRun) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	finishedAt := time.Now()
+	if run.FinishedAt != nil {
+		finishedAt = *run.FinishedAt
+	}
+
+	_, err := w.pool.Exec(ctx, `
+	UPDATE scrape_runs SET
+		finished_at = $2,
+		status = $3,
+		sections_visited = $4,
+		properties_scraped = $5,
+		properties_failed = $6,
+		listings_saved = $7,
+		error_summary = NULLIF($8, '')
+	WHERE id = $1;
+	`, run.ID, finishedAt, run.Status, run.SectionsVisited, run.PropertiesScraped,
+		run.PropertiesFailed, run.ListingsSaved, run.ErrorSummary)
+	if err != nil {
+		return fmt.Errorf("failed to record run end: %w", err)
+	}
+	return nil
+}
+
+// LastRun returns the most recently started run, or nil if there is none.
+func (w *PostgresWriter) LastRun() (*ScrapeRun, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var run ScrapeRun
+	err := w.pool.QueryRow(ctx, `
+	SELECT id, started_at, finished_at, status, version, config,
+	       sections_visited, properties_scraped, properties_failed, listings_saved,
+	       COALESCE(error_summary, '')
+	FROM scrape_runs
+	ORDER BY started_at DESC
+	LIMIT 1;
+	`).Scan(&run.ID, &run.StartedAt, &run.FinishedAt, &run.Status, &run.Version, &run.Config,
+		&run.SectionsVisited, &run.PropertiesScraped, &run.PropertiesFailed, &run.ListingsSaved,
+		&run.ErrorSummary)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read last run: %w", err)
+	}
+	return &run, nil
+}
@@ -1,17 +1,35 @@
 package utils
 
 import (
+	"context"
 	"math/rand"
 	"time"
 )
 
 // RandomDelay sleeps for a random duration between min and max.
-// Pass time.Duration values like: RandomDelay(2*time.Second, 5*time.Second)
+// Pass time.Duration values like: RandomDelay(ctx, 2*time.Second, 5*time.Second)
 //
 // WHY RANDOM? Fixed delays are detectable patterns.
 // Random delays look more like a human browsing.
-func RandomDelay(min, max time.Duration) {
-	diff := max - min
-	sleep := min + time.Duration(rand.Int63n(int64(diff)))
-	time.Sleep(sleep)
-}
\ No newline at end of file
+//
+// It returns ctx.Err() early if ctx is cancelled while sleeping.
+func RandomDelay(ctx context.Context, min, max time.Duration) error {
+	sleep := min
+	if diff := max - min; diff > 0 {
+		sleep += time.Duration(rand.Int63n(int64(diff)))
+	}
+	return Sleep(ctx, sleep)
+}
+
+// Sleep waits for d, or until ctx is cancelled (returning ctx.Err()).
+func Sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
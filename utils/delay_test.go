@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRandomDelayCancelled(t *testing.T) {
+	tests := []struct {
+		name   string
+		cancel func(context.CancelFunc)
+	}{
+		{"cancelled before", func(cancel context.CancelFunc) { cancel() }},
+		{"cancelled while sleeping", func(cancel context.CancelFunc) { time.AfterFunc(50*time.Millisecond, cancel) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			tt.cancel(cancel)
+
+			start := time.Now()
+			err := RandomDelay(ctx, time.Hour, 2*time.Hour)
+			if err != context.Canceled {
+				t.Errorf("RandomDelay = %v, want %v", err, context.Canceled)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("RandomDelay returned after %v, want it to return promptly", elapsed)
+			}
+		})
+	}
+}
+
+func TestRandomDelayDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := RandomDelay(ctx, time.Hour, time.Hour); err != context.DeadlineExceeded {
+		t.Errorf("RandomDelay = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRandomDelayRange(t *testing.T) {
+	const min, max = 20 * time.Millisecond, 40 * time.Millisecond
+	for i := 0; i < 5; i++ {
+		start := time.Now()
+		if err := RandomDelay(context.Background(), min, max); err != nil {
+			t.Fatal(err)
+		}
+		if elapsed := time.Since(start); elapsed < min {
+			t.Errorf("RandomDelay slept %v, want at least %v", elapsed, min)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -18,15 +19,25 @@ import (
 // WHY? If Airbnb is rate-limiting you, hammering it again immediately
 // makes it worse. Waiting longer each time gives it time to settle.
 //
+// Once ctx is cancelled no new attempt is started and the backoff wait
+// is cut short; the attempt already running is left to finish.
+//
 // Usage:
 //
-//	err := utils.Retry(3, func() error {
+//	err := utils.Retry(ctx, 3, func() error {
 //	    return scraper.ScrapePage(url)
 //	})
-func Retry(maxRetries int, fn func() error) error {
+func Retry(ctx context.Context, maxRetries int, fn func() error) error {
 	var lastErr error
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
+		if err := ctx.Err(); err != nil {
+			if lastErr == nil {
+				return err
+			}
+			return fmt.Errorf("stopped after %d attempts: %w (last error: %v)", attempt-1, err, lastErr)
+		}
+
 		lastErr = fn()
 		if lastErr == nil {
 			return nil // success — stop retrying
@@ -35,7 +46,7 @@ func Retry(maxRetries int, fn func() error) error {
 		if attempt < maxRetries {
 			wait := time.Duration(1<<uint(attempt)) * time.Second // 2s, 4s, 8s...
 			Warn("Attempt %d/%d failed: %v — retrying in %v", attempt, maxRetries, lastErr, wait)
-			time.Sleep(wait)
+			Sleep(ctx, wait)
 		}
 	}
 
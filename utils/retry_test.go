@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryStopsWhenCancelled(t *testing.T) {
+	failure := errors.New("rate limited")
+
+	tests := []struct {
+		name string
+		// cancel is called during the first attempt.
+		cancel func(context.CancelFunc)
+	}{
+		{"cancelled during an attempt", func(cancel context.CancelFunc) { cancel() }},
+		{"cancelled during the backoff wait", func(cancel context.CancelFunc) { time.AfterFunc(50*time.Millisecond, cancel) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			calls := 0
+			start := time.Now()
+			err := Retry(ctx, 3, func() error {
+				calls++
+				if calls == 1 {
+					tt.cancel(cancel)
+				}
+				return failure
+			})
+
+			if calls != 1 {
+				t.Errorf("fn called %d times, want 1", calls)
+			}
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("Retry = %v, want context.Canceled", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("Retry returned after %v, want it to cut the 2s backoff short", elapsed)
+			}
+		})
+	}
+}
+
+func TestRetryCancelledBeforeStart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	called := false
+	err := Retry(ctx, 3, func() error {
+		called = true
+		return nil
+	})
+	if called {
+		t.Error("fn called on a cancelled context")
+	}
+	if err != context.Canceled {
+		t.Errorf("Retry = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"context"
+	"time"
+)
+
+// WithGrace returns a context that outlives ctx by grace: it keeps ctx's
+// values but is only cancelled grace after ctx is done (or when cancel is
+// called). Work already in flight at shutdown runs on it, so it gets a
+// bounded amount of time to finish instead of being cut off mid-page.
+func WithGrace(ctx context.Context, grace time.Duration) (context.Context, context.CancelFunc) {
+	drain, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-drain.Done():
+			return
+		}
+		t := time.NewTimer(grace)
+		defer t.Stop()
+		select {
+		case <-t.C:
+			cancel()
+		case <-drain.Done():
+		}
+	}()
+
+	return drain, cancel
+}
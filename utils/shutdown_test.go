@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type ctxKey struct{}
+
+func TestWithGrace(t *testing.T) {
+	const grace = 200 * time.Millisecond
+
+	parent, cancelParent := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "run"))
+	drain, cancel := WithGrace(parent, grace)
+	defer cancel()
+
+	if got := drain.Value(ctxKey{}); got != "run" {
+		t.Errorf("drain.Value = %v, want the parent's value", got)
+	}
+
+	start := time.Now()
+	cancelParent()
+
+	select {
+	case <-drain.Done():
+		t.Fatalf("drain context cancelled %v after the parent, want %v", time.Since(start), grace)
+	case <-time.After(grace / 2):
+	}
+
+	select {
+	case <-drain.Done():
+		if elapsed := time.Since(start); elapsed < grace {
+			t.Errorf("drain context cancelled after %v, want at least %v", elapsed, grace)
+		}
+	case <-time.After(5 * grace):
+		t.Fatal("drain context not cancelled after the grace period")
+	}
+	if drain.Err() != context.Canceled {
+		t.Errorf("drain.Err() = %v, want %v", drain.Err(), context.Canceled)
+	}
+}
+
+func TestWithGraceCancel(t *testing.T) {
+	parent, cancelParent := context.WithCancel(context.Background())
+	defer cancelParent()
+
+	drain, cancel := WithGrace(parent, time.Hour)
+	cancel()
+
+	select {
+	case <-drain.Done():
+	case <-time.After(time.Second):
+		t.Fatal("cancel did not cancel the drain context")
+	}
+	if parent.Err() != nil {
+		t.Error("cancel cancelled the parent")
+	}
+}